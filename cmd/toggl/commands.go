@@ -0,0 +1,419 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+	"github.com/gedex/go-toggl/toggl/csvio"
+)
+
+// createdWith identifies time entries created by this command.
+const createdWith = "toggl-cli"
+
+func cmdStart(a *app, args []string) error {
+	fs := a.flagSet("start", "[description]")
+	project := fs.String("p", "", "project name or ID")
+	task := fs.String("t", "", "task name or ID, requires -p")
+	tags := fs.String("tags", "", "comma separated `tags`")
+	billable := fs.Bool("b", false, "billable")
+	if err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	wid, err := a.workspace()
+	if err != nil {
+		return err
+	}
+	te := &toggl.TimeEntry{
+		WorkspaceID: wid,
+		Description: strings.Join(fs.Args(), " "),
+		Billable:    *billable,
+		CreatedWith: createdWith,
+	}
+	for _, tag := range strings.Split(*tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			te.Tags = append(te.Tags, tag)
+		}
+	}
+
+	if *task != "" && *project == "" {
+		return errors.New("-t requires -p")
+	}
+	if *project != "" {
+		var names *csvio.Names
+		if te.ProjectID, names, err = a.resolve(wid, *project, nil, func(n *csvio.Names, s string) (int, error) {
+			return n.ProjectID("", s)
+		}); err != nil {
+			return err
+		}
+		if *task != "" {
+			if te.TaskID, _, err = a.resolve(wid, *task, names, func(n *csvio.Names, s string) (int, error) {
+				return n.TaskID(te.ProjectID, s)
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	te, err = a.client.TimeEntries.StartContext(a.ctx, te)
+	if err != nil {
+		return err
+	}
+	return a.printEntry(te)
+}
+
+// resolve returns the ID in s, or looks up the ID of the name s with
+// lookup, loading the names of the workspace unless given.
+func (a *app) resolve(wid int, s string, names *csvio.Names, lookup func(*csvio.Names, string) (int, error)) (int, *csvio.Names, error) {
+	if id, err := strconv.Atoi(s); err == nil {
+		return id, names, nil
+	}
+	if names == nil {
+		var err error
+		if names, err = csvio.LoadNames(a.ctx, a.client, wid); err != nil {
+			return 0, nil, err
+		}
+	}
+	id, err := lookup(names, s)
+	return id, names, err
+}
+
+func cmdStop(a *app, args []string) error {
+	fs := a.flagSet("stop", "")
+	if err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	te, err := a.client.TimeEntries.CurrentContext(a.ctx)
+	if err != nil {
+		return err
+	}
+	if te == nil {
+		return errors.New("no time entry is running")
+	}
+	if te, err = a.client.TimeEntries.StopContext(a.ctx, te.ID); err != nil {
+		return err
+	}
+	return a.printEntry(te)
+}
+
+func cmdCurrent(a *app, args []string) error {
+	fs := a.flagSet("current", "")
+	if err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	te, err := a.client.TimeEntries.CurrentContext(a.ctx)
+	if err != nil {
+		return err
+	}
+	if te == nil && a.format == formatTable {
+		fmt.Fprintln(a.stdout, "No time entry is running")
+		return nil
+	}
+	return a.printEntry(te)
+}
+
+// printEntry prints te, which may be nil, along with its project name.
+func (a *app) printEntry(te *toggl.TimeEntry) error {
+	var entries []toggl.TimeEntry
+	var projects []toggl.Project
+	if te != nil {
+		entries = append(entries, *te)
+		if te.ProjectID != 0 && a.format != formatJSON {
+			p, err := a.client.Projects.GetContext(a.ctx, te.ProjectID)
+			if err != nil {
+				return err
+			}
+			if p != nil {
+				projects = append(projects, *p)
+			}
+		}
+	}
+
+	t := entriesTable(entries, csvio.NewNames(projects, nil, nil, nil))
+	t.value = te
+	return a.print(t)
+}
+
+func entriesTable(entries []toggl.TimeEntry, names *csvio.Names) *table {
+	t := &table{
+		header: []string{"id", "start", "stop", "duration", "project", "description", "tags"},
+		value:  entries,
+	}
+	now := time.Now()
+	for _, te := range entries {
+		t.add(
+			strconv.Itoa(te.ID),
+			formatTime(te.Start),
+			formatTime(te.Stop),
+			formatDuration(te.Elapsed(now)),
+			names.ProjectName(te.ProjectID),
+			te.Description,
+			strings.Join(te.Tags, ", "),
+		)
+	}
+	return t
+}
+
+func cmdLs(a *app, args []string) error {
+	fs := a.flagSet("ls", "")
+	since := fs.String("since", "", "first `date` to list, as YYYY-MM-DD (default 6 days ago)")
+	until := fs.String("until", "", "last `date` to list, as YYYY-MM-DD (default today)")
+	if err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	start, end, err := dateRange(*since, *until)
+	if err != nil {
+		return err
+	}
+
+	var entries []toggl.TimeEntry
+	it := a.client.TimeEntries.ListIterContext(a.ctx, start, end)
+	for it.Next() {
+		entries = append(entries, it.Entry())
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	if a.format == formatJSON {
+		return a.print(&table{value: entries})
+	}
+
+	wid, err := a.workspace()
+	if err != nil {
+		return err
+	}
+	names, err := csvio.LoadNames(a.ctx, a.client, wid)
+	if err != nil {
+		return err
+	}
+	if a.format == formatCSV {
+		// Toggl's own export columns, which csvio can read back.
+		return csvio.NewWriter(a.stdout, names).WriteAll(entries)
+	}
+	return a.print(entriesTable(entries, names))
+}
+
+// dateRange returns the range from the start of the since date to the end
+// of the until date, both in the local time zone.
+func dateRange(since, until string) (time.Time, time.Time, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	start, end := today.AddDate(0, 0, -6), today
+	var err error
+	if since != "" {
+		if start, err = time.ParseInLocation("2006-01-02", since, time.Local); err != nil {
+			return start, end, fmt.Errorf("invalid date %q", since)
+		}
+	}
+	if until != "" {
+		if end, err = time.ParseInLocation("2006-01-02", until, time.Local); err != nil {
+			return start, end, fmt.Errorf("invalid date %q", until)
+		}
+	}
+	if end.Before(start) {
+		return start, end, errors.New("-until is before -since")
+	}
+	return start, end.AddDate(0, 0, 1).Add(-time.Second), nil
+}
+
+func cmdReport(a *app, args []string) error {
+	fs := a.flagSet("report", "")
+	since := fs.String("since", "", "first `date` of the report, as YYYY-MM-DD (default 6 days ago)")
+	until := fs.String("until", "", "last `date` of the report, as YYYY-MM-DD (default today)")
+	group := fs.String("group", toggl.GroupByProjects, "`grouping`: projects, clients or users")
+	subgroup := fs.String("sub", "", "`subgrouping`: time_entries, tasks, projects, clients or users")
+	if err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	start, end, err := dateRange(*since, *until)
+	if err != nil {
+		return err
+	}
+	wid, err := a.workspace()
+	if err != nil {
+		return err
+	}
+
+	r, err := a.client.Reports.SummaryContext(a.ctx, &toggl.ReportOptions{
+		WorkspaceID: wid,
+		Since:       start,
+		Until:       end,
+		Grouping:    *group,
+		Subgrouping: *subgroup,
+	})
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"group", "item", "duration", "amount"}, value: r}
+	for _, g := range r.Data {
+		t.add(title(g.Title), "", formatDuration(time.Duration(g.Time)*time.Millisecond), formatAmounts(g.TotalCurrencies))
+		for _, item := range g.Items {
+			var amount string
+			if item.Sum != 0 {
+				amount = formatAmounts([]toggl.ReportCurrency{{Currency: item.Currency, Amount: item.Sum}})
+			}
+			t.add("", title(item.Title), formatDuration(time.Duration(item.Time)*time.Millisecond), amount)
+		}
+	}
+	t.add("Total", "", formatDuration(time.Duration(r.TotalGrand)*time.Millisecond), formatAmounts(r.TotalCurrencies))
+	return a.print(t)
+}
+
+// title returns the set field of a report title.
+func title(t toggl.ReportTitle) string {
+	for _, s := range []string{t.TimeEntry, t.Task, t.Project, t.Client, t.User} {
+		if s != "" {
+			return s
+		}
+	}
+	return "(none)"
+}
+
+func formatAmounts(amounts []toggl.ReportCurrency) string {
+	var s []string
+	for _, a := range amounts {
+		if a.Amount != 0 {
+			s = append(s, fmt.Sprintf("%.2f %s", a.Amount, a.Currency))
+		}
+	}
+	return strings.Join(s, ", ")
+}
+
+func cmdProjects(a *app, args []string) error {
+	fs := a.flagSet("projects", "")
+	if err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	wid, err := a.workspace()
+	if err != nil {
+		return err
+	}
+	projects, err := a.client.Workspaces.ListProjectsContext(a.ctx, wid, "")
+	if err != nil {
+		return err
+	}
+	clients, err := a.client.Workspaces.ListClientsContext(a.ctx, wid)
+	if err != nil {
+		return err
+	}
+	names := csvio.NewNames(projects, clients, nil, nil)
+
+	t := &table{header: []string{"id", "name", "client", "billable", "active"}, value: projects}
+	for _, p := range projects {
+		t.add(strconv.Itoa(p.ID), p.Name, names.ClientName(p.ID), strconv.FormatBool(p.Billable), strconv.FormatBool(p.Active))
+	}
+	return a.print(t)
+}
+
+func cmdClients(a *app, args []string) error {
+	fs := a.flagSet("clients", "")
+	if err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	wid, err := a.workspace()
+	if err != nil {
+		return err
+	}
+	clients, err := a.client.Workspaces.ListClientsContext(a.ctx, wid)
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"id", "name", "rate", "currency"}, value: clients}
+	for _, c := range clients {
+		var rate string
+		if c.HourlyRate != 0 {
+			rate = strconv.FormatFloat(c.HourlyRate, 'f', -1, 64)
+		}
+		t.add(strconv.Itoa(c.ID), c.Name, rate, c.Currency)
+	}
+	return a.print(t)
+}
+
+func cmdTags(a *app, args []string) error {
+	fs := a.flagSet("tags", "")
+	if err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	wid, err := a.workspace()
+	if err != nil {
+		return err
+	}
+	tags, err := a.client.Workspaces.ListTagsContext(a.ctx, wid)
+	if err != nil {
+		return err
+	}
+	if tags == nil {
+		// Toggl sends null for a workspace without tags.
+		tags = []toggl.Tag{}
+	}
+
+	t := &table{header: []string{"id", "name"}, value: tags}
+	for _, tag := range tags {
+		t.add(strconv.Itoa(tag.ID), tag.Name)
+	}
+	return a.print(t)
+}
+
+func cmdTasks(a *app, args []string) error {
+	fs := a.flagSet("tasks", "")
+	project := fs.String("p", "", "only list tasks of the project with this name or ID")
+	if err := a.parse(fs, args); err != nil {
+		return err
+	}
+
+	wid, err := a.workspace()
+	if err != nil {
+		return err
+	}
+	tasks, err := a.client.Workspaces.ListTasksContext(a.ctx, wid, "")
+	if err != nil {
+		return err
+	}
+	projects, err := a.client.Workspaces.ListProjectsContext(a.ctx, wid, "")
+	if err != nil {
+		return err
+	}
+	names := csvio.NewNames(projects, nil, tasks, nil)
+
+	if *project != "" {
+		pid, _, err := a.resolve(wid, *project, names, func(n *csvio.Names, s string) (int, error) {
+			return n.ProjectID("", s)
+		})
+		if err != nil {
+			return err
+		}
+		filtered := []toggl.Task{}
+		for _, task := range tasks {
+			if task.ProjectID == pid {
+				filtered = append(filtered, task)
+			}
+		}
+		tasks = filtered
+	}
+
+	t := &table{header: []string{"id", "name", "project", "active"}, value: tasks}
+	for _, task := range tasks {
+		t.add(strconv.Itoa(task.ID), task.Name, names.ProjectName(task.ProjectID), strconv.FormatBool(task.Active))
+	}
+	return a.print(t)
+}
@@ -0,0 +1,109 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+// Environment variables overriding the config file.
+const (
+	envAPIToken   = "TOGGL_API_TOKEN"
+	envAPIURL     = "TOGGL_API_URL"
+	envReportsURL = "TOGGL_REPORTS_URL"
+	envConfig     = "TOGGL_CONFIG"
+)
+
+// config represents the config file.
+type config struct {
+	APIToken    string `json:"api_token,omitempty"`
+	WorkspaceID int    `json:"workspace_id,omitempty"`
+
+	// Base URLs of the APIs, defaulting to toggl.BaseURL and
+	// toggl.ReportsBaseURL
+	APIURL     string `json:"api_url,omitempty"`
+	ReportsURL string `json:"reports_url,omitempty"`
+}
+
+// loadConfig reads the config file at path, or at the default path if
+// path is empty, and applies the environment variables. A missing default
+// config file is not an error.
+func loadConfig(path string, getenv func(string) string) (*config, error) {
+	cfg := &config{}
+
+	explicit := path != ""
+	if !explicit {
+		path = getenv(envConfig)
+		explicit = path != ""
+	}
+	if !explicit {
+		if dir, err := os.UserConfigDir(); err == nil {
+			path = filepath.Join(dir, "toggl", "config.json")
+		}
+	}
+
+	if path != "" {
+		b, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if len(bytes.TrimSpace(b)) == 0 {
+				break
+			}
+			if err := json.Unmarshal(b, cfg); err != nil {
+				return nil, fmt.Errorf("%s: %v", path, err)
+			}
+		case explicit || !errors.Is(err, os.ErrNotExist):
+			return nil, err
+		}
+	}
+
+	if v := getenv(envAPIToken); v != "" {
+		cfg.APIToken = v
+	}
+	if v := getenv(envAPIURL); v != "" {
+		cfg.APIURL = v
+	}
+	if v := getenv(envReportsURL); v != "" {
+		cfg.ReportsURL = v
+	}
+	return cfg, nil
+}
+
+// client returns a Toggl client configured by cfg.
+func (cfg *config) client() (*toggl.Client, error) {
+	if cfg.APIToken == "" {
+		return nil, fmt.Errorf("no API token; set %s or api_token in the config file", envAPIToken)
+	}
+
+	var opts []toggl.ClientOption
+	if cfg.APIURL != "" {
+		u, err := url.Parse(cfg.APIURL)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, toggl.WithBaseURL(u))
+	}
+	if cfg.ReportsURL != "" {
+		u, err := url.Parse(cfg.ReportsURL)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, toggl.WithReportsBaseURL(u))
+	}
+	opts = append(opts,
+		toggl.WithUserAgent("toggl-cli "+toggl.UserAgent),
+		toggl.WithRetryPolicy(&toggl.RetryPolicy{MaxAttempts: 3}),
+	)
+	return toggl.NewClient(cfg.APIToken, opts...), nil
+}
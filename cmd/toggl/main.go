@@ -0,0 +1,195 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Command toggl tracks time and lists Toggl data from the command line.
+
+Usage:
+
+	toggl command [flags] [arguments]
+
+The commands are:
+
+	start     start a time entry
+	stop      stop the running time entry
+	current   show the running time entry
+	ls        list time entries
+	report    show a summary report
+	projects  list projects
+	clients   list clients
+	tags      list tags
+	tasks     list tasks
+
+Every command accepts these flags:
+
+	-o format     output format: table, json or csv (default table)
+	-w id         workspace ID (default from the config file, else the
+	              user's default workspace)
+	-config path  config file
+
+The API token is read from the TOGGL_API_TOKEN environment variable or the
+config file, by default toggl/config.json in the user's config directory
+(see os.UserConfigDir), a JSON object such as:
+
+	{"api_token": "YOUR_API_TOKEN", "workspace_id": 123}
+*/
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+// command is a subcommand, which parses its own flags with app.parse.
+type command struct {
+	help string
+	run  func(a *app, args []string) error
+}
+
+var commands map[string]command
+
+func init() {
+	// Set in init, as the commands refer to it for their usage.
+	commands = map[string]command{
+		"start":    {"start a time entry", cmdStart},
+		"stop":     {"stop the running time entry", cmdStop},
+		"current":  {"show the running time entry", cmdCurrent},
+		"ls":       {"list time entries", cmdLs},
+		"report":   {"show a summary report", cmdReport},
+		"projects": {"list projects", cmdProjects},
+		"clients":  {"list clients", cmdClients},
+		"tags":     {"list tags", cmdTags},
+		"tasks":    {"list tasks", cmdTasks},
+	}
+}
+
+// errUsage is returned by commands after printing their usage.
+var errUsage = errors.New("usage")
+
+// app holds the state shared by commands.
+type app struct {
+	ctx    context.Context
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+
+	// Set by the common flags
+	format      string
+	workspaceID int
+	configPath  string
+
+	client *toggl.Client
+}
+
+func main() {
+	a := &app{
+		ctx:    context.Background(),
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		getenv: os.Getenv,
+	}
+	os.Exit(a.run(os.Args[1:]))
+}
+
+// run runs the command in args and returns the exit code.
+func (a *app) run(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+		a.usage()
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(a.stderr, "toggl: unknown command %q\n", args[0])
+		a.usage()
+		return 2
+	}
+
+	if err := cmd.run(a, args[1:]); err == errUsage {
+		return 2
+	} else if err != nil {
+		fmt.Fprintf(a.stderr, "toggl %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+func (a *app) usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprint(a.stderr, "Usage: toggl command [flags] [arguments]\n\nCommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(a.stderr, "  %-9s %s\n", name, commands[name].help)
+	}
+	fmt.Fprint(a.stderr, "\nRun 'toggl command -h' for the flags of a command.\n")
+}
+
+// flagSet returns a FlagSet for the named command with the common flags.
+func (a *app) flagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		help := commands[name].help
+		fmt.Fprintf(a.stderr, "Usage: toggl %s [flags] %s\n\n%s.\n\nFlags:\n", name, args, strings.ToUpper(help[:1])+help[1:])
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&a.format, "o", formatTable, "output `format`: table, json or csv")
+	fs.IntVar(&a.workspaceID, "w", 0, "workspace `id`")
+	fs.StringVar(&a.configPath, "config", "", "config file `path`")
+	return fs
+}
+
+// parse parses args with fs and sets up the client.
+func (a *app) parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	switch a.format {
+	case formatTable, formatJSON, formatCSV:
+	default:
+		return fmt.Errorf("unknown output format %q", a.format)
+	}
+
+	cfg, err := loadConfig(a.configPath, a.getenv)
+	if err != nil {
+		return err
+	}
+	if a.client, err = cfg.client(); err != nil {
+		return err
+	}
+	if a.workspaceID == 0 {
+		a.workspaceID = cfg.WorkspaceID
+	}
+	return nil
+}
+
+// workspace returns the workspace ID set by flag or config, falling back
+// to the user's default workspace.
+func (a *app) workspace() (int, error) {
+	if a.workspaceID != 0 {
+		return a.workspaceID, nil
+	}
+	me, err := a.client.Users.MeContext(a.ctx, false)
+	if err != nil {
+		return 0, err
+	}
+	if me == nil {
+		return 0, errors.New("no user returned")
+	}
+	a.workspaceID = me.DefautWID
+	return a.workspaceID, nil
+}
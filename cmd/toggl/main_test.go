@@ -0,0 +1,239 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var (
+	// mux is the HTTP request multiplexer used with the test server.
+	mux *http.ServeMux
+
+	// server is a test HTTP server to provide mock API response.
+	server *httptest.Server
+)
+
+// setup sets up a test HTTP server for the commands to talk to. Tests
+// should register handlers on mux which provide responses for the API
+// methods being used.
+func setup() {
+	mux = http.NewServeMux()
+	server = httptest.NewServer(mux)
+}
+
+// teardown closes the test HTTP server.
+func teardown() {
+	server.Close()
+}
+
+// run runs toggl with args against the test server and returns the exit
+// code and output. env overrides the environment.
+func run(env map[string]string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	a := &app{
+		ctx:    context.Background(),
+		stdout: &stdout,
+		stderr: &stderr,
+		getenv: func(key string) string {
+			if v, ok := env[key]; ok {
+				return v
+			}
+			switch key {
+			case envAPIToken:
+				return "token"
+			case envAPIURL:
+				return server.URL + "/"
+			case envConfig:
+				return os.DevNull
+			}
+			return ""
+		},
+	}
+	code := a.run(args)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestProjects(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/workspaces/1/projects", func(w http.ResponseWriter, r *http.Request) {
+		if _, pass, _ := r.BasicAuth(); pass != "api_token" {
+			t.Errorf("Request password = %q, want api_token", pass)
+		}
+		fmt.Fprint(w, `[{"id": 100, "cid": 10, "name": "Website", "billable": true, "active": true}]`)
+	})
+	mux.HandleFunc("/workspaces/1/clients", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": 10, "name": "Acme"}]`)
+	})
+
+	code, stdout, stderr := run(nil, "projects", "-w", "1")
+	if code != 0 {
+		t.Fatalf("toggl projects exited with %d: %s", code, stderr)
+	}
+	want := "ID   NAME     CLIENT  BILLABLE  ACTIVE\n" +
+		"100  Website  Acme    true      true\n"
+	if stdout != want {
+		t.Errorf("toggl projects printed\n%s\nwant\n%s", stdout, want)
+	}
+
+	_, stdout, _ = run(nil, "projects", "-w", "1", "-o", "csv")
+	if want := "id,name,client,billable,active\n100,Website,Acme,true,true\n"; stdout != want {
+		t.Errorf("toggl projects -o csv printed %q, want %q", stdout, want)
+	}
+}
+
+func TestTags(t *testing.T) {
+	setup()
+	defer teardown()
+
+	body := `[{"id": 1, "wid": 1, "name": "billed"}]`
+	mux.HandleFunc("/workspaces/1/tags", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+
+	if _, stdout, _ := run(nil, "tags", "-w", "1", "-o", "csv"); stdout != "id,name\n1,billed\n" {
+		t.Errorf("toggl tags -o csv printed %q", stdout)
+	}
+
+	body = `null`
+	code, stdout, stderr := run(nil, "tags", "-w", "1", "-o", "json")
+	if code != 0 || stdout != "[]\n" {
+		t.Errorf("toggl tags -o json without tags exited with %d and printed %q, %q, want []", code, stdout, stderr)
+	}
+}
+
+func TestStart(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"id": 7, "default_wid": 1}}`)
+	})
+	for _, path := range []string{"/workspaces/1/clients", "/workspaces/1/tasks", "/workspaces/1/users"} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[]`)
+		})
+	}
+	mux.HandleFunc("/workspaces/1/projects", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": 100, "wid": 1, "name": "Website"}]`)
+	})
+	mux.HandleFunc("/time_entries/start", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			TimeEntry map[string]interface{} `json:"time_entry"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		got := fmt.Sprint(body.TimeEntry)
+		want := "map[created_with:toggl-cli description:Fix bug pid:100 tags:[a b] wid:1]"
+		if got != want {
+			t.Errorf("Request body = %v, want %v", got, want)
+		}
+		fmt.Fprint(w, `{"data": {"id": 1, "pid": 100, "description": "Fix bug"}}`)
+	})
+
+	code, stdout, stderr := run(nil, "start", "-o", "json", "-p", "website", "-tags", "a, b", "Fix", "bug")
+	if code != 0 {
+		t.Fatalf("toggl start exited with %d: %s", code, stderr)
+	}
+	if want := "{\n  \"id\": 1,\n  \"pid\": 100,\n  \"description\": \"Fix bug\"\n}\n"; stdout != want {
+		t.Errorf("toggl start printed %q, want %q", stdout, want)
+	}
+}
+
+func TestCurrent_none(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/time_entries/current", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": null}`)
+	})
+
+	if _, stdout, _ := run(nil, "current"); stdout != "No time entry is running\n" {
+		t.Errorf("toggl current printed %q", stdout)
+	}
+	if _, stdout, _ := run(nil, "current", "-o", "json"); stdout != "null\n" {
+		t.Errorf("toggl current -o json printed %q, want null", stdout)
+	}
+
+	code, _, stderr := run(nil, "stop")
+	if want := "toggl stop: no time entry is running\n"; code != 1 || stderr != want {
+		t.Errorf("toggl stop exited with %d and printed %q, want 1 and %q", code, stderr, want)
+	}
+}
+
+func TestCurrent_missingProject(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/time_entries/current", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"id": 1, "pid": 100, "start": "2013-07-01T09:00:00Z", "duration": -1372669200}}`)
+	})
+	mux.HandleFunc("/projects/100", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": null}`)
+	})
+
+	if code, _, stderr := run(nil, "current"); code != 0 {
+		t.Errorf("toggl current exited with %d: %s", code, stderr)
+	}
+}
+
+func TestWorkspace_noUser(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": null}`)
+	})
+
+	code, _, stderr := run(nil, "projects")
+	if want := "toggl projects: no user returned\n"; code != 1 || stderr != want {
+		t.Errorf("toggl projects exited with %d and printed %q, want 1 and %q", code, stderr, want)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/workspaces/2/clients", func(w http.ResponseWriter, r *http.Request) {
+		if user, _, _ := r.BasicAuth(); user != "file-token" {
+			t.Errorf("Request token = %q, want file-token", user)
+		}
+		fmt.Fprint(w, `[]`)
+	})
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{"api_token": "file-token", "workspace_id": 2}`), 0600)
+
+	env := map[string]string{envAPIToken: ""}
+	if code, _, stderr := run(env, "clients", "-config", path); code != 0 {
+		t.Errorf("toggl clients exited with %d: %s", code, stderr)
+	}
+
+	code, _, stderr := run(env, "clients")
+	if code != 1 || !strings.Contains(stderr, "no API token") {
+		t.Errorf("toggl clients without token exited with %d and printed %q", code, stderr)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	if code, _, stderr := run(nil, "nope"); code != 2 || !strings.Contains(stderr, `unknown command "nope"`) {
+		t.Errorf("toggl nope exited with %d and printed %q", code, stderr)
+	}
+	if code, _, stderr := run(nil, "ls", "-o", "xml"); code != 1 || !strings.Contains(stderr, `unknown output format "xml"`) {
+		t.Errorf("toggl ls -o xml exited with %d and printed %q", code, stderr)
+	}
+}
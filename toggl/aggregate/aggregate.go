@@ -0,0 +1,204 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package aggregate groups and sums Toggl time entries offline, e.g. to get
+the billable and non-billable time per project and week:
+
+	entries, err := c.TimeEntries.List(&start, &end)
+	...
+	totals := aggregate.Sum(entries, &aggregate.Options{
+		BeginningOfWeek: me.BeginningOfWeek,
+	}, aggregate.Project, aggregate.Week)
+	for _, t := range totals {
+		fmt.Println(t.Period, t.ProjectID, t.Billable, t.NonBillable)
+	}
+*/
+package aggregate
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+// Dimension is a property time entries are grouped by.
+type Dimension int
+
+// Dimensions time entries can be grouped by. Day, Week and Month group by
+// the period the entry started in.
+const (
+	Workspace Dimension = iota
+	Client
+	Project
+	Task
+	User
+	Tag
+	Day
+	Week
+	Month
+)
+
+// Key identifies a group of time entries. Only the fields of the
+// dimensions grouped by are set.
+type Key struct {
+	WorkspaceID int
+	ClientID    int
+	ProjectID   int
+	TaskID      int
+	UserID      int
+	Tag         string
+
+	// Start of the day, week or month
+	Period time.Time
+}
+
+// Total represents the time tracked by a group of time entries.
+type Total struct {
+	Key
+
+	// Number of time entries in the group
+	Count int
+
+	Duration    time.Duration
+	Billable    time.Duration
+	NonBillable time.Duration
+}
+
+// Options configures how time entries are aggregated.
+type Options struct {
+	// Projects of the time entries, used to group by client. Entries of
+	// other projects have no client.
+	Projects []toggl.Project
+
+	// First day of the week when grouping by Week, Sunday=0 as in
+	// toggl.User.BeginningOfWeek
+	BeginningOfWeek int
+
+	// Location days, weeks and months are computed in. Defaults to
+	// time.Local.
+	Location *time.Location
+
+	// Time running entries are measured up to. Defaults to time.Now().
+	Now time.Time
+}
+
+// Sum groups entries by the given dimensions and returns the total of each
+// group, ordered by key. Without dimensions a single grand total is
+// returned. When grouping by Tag, an entry counts towards each of its tags
+// and entries without tags are grouped under the empty tag. opts may be
+// nil.
+func Sum(entries []toggl.TimeEntry, opts *Options, dims ...Dimension) []Total {
+	if opts == nil {
+		opts = &Options{}
+	}
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	clients := make(map[int]int, len(opts.Projects))
+	for _, p := range opts.Projects {
+		clients[p.ID] = p.ClientID
+	}
+
+	groups := make(map[Key]*Total)
+	add := func(k Key, te *toggl.TimeEntry, d time.Duration) {
+		t := groups[k]
+		if t == nil {
+			t = &Total{Key: k}
+			groups[k] = t
+		}
+		t.Count++
+		t.Duration += d
+		if te.Billable {
+			t.Billable += d
+		} else {
+			t.NonBillable += d
+		}
+	}
+
+	for i := range entries {
+		te := &entries[i]
+		d := te.Elapsed(now)
+
+		var k Key
+		tagged := false
+		for _, dim := range dims {
+			switch dim {
+			case Workspace:
+				k.WorkspaceID = te.WorkspaceID
+			case Client:
+				k.ClientID = clients[te.ProjectID]
+			case Project:
+				k.ProjectID = te.ProjectID
+			case Task:
+				k.TaskID = te.TaskID
+			case User:
+				k.UserID = te.UserID
+			case Tag:
+				tagged = true
+			case Day, Week, Month:
+				if te.Start != nil {
+					k.Period = period(te.Start.In(loc), dim, opts.BeginningOfWeek)
+				}
+			}
+		}
+
+		if !tagged || len(te.Tags) == 0 {
+			add(k, te, d)
+			continue
+		}
+		for _, tag := range te.Tags {
+			k.Tag = tag
+			add(k, te, d)
+		}
+	}
+
+	totals := make([]Total, 0, len(groups))
+	for _, t := range groups {
+		totals = append(totals, *t)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].Key.less(totals[j].Key)
+	})
+	return totals
+}
+
+// period returns the start of the day, week or month t falls in.
+func period(t time.Time, dim Dimension, beginningOfWeek int) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch dim {
+	case Week:
+		offset := (int(day.Weekday()) - beginningOfWeek%7 + 7) % 7
+		return day.AddDate(0, 0, -offset)
+	case Month:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+func (k Key) less(o Key) bool {
+	if !k.Period.Equal(o.Period) {
+		return k.Period.Before(o.Period)
+	}
+	for _, p := range [][2]int{
+		{k.WorkspaceID, o.WorkspaceID},
+		{k.ClientID, o.ClientID},
+		{k.ProjectID, o.ProjectID},
+		{k.TaskID, o.TaskID},
+		{k.UserID, o.UserID},
+	} {
+		if p[0] != p[1] {
+			return p[0] < p[1]
+		}
+	}
+	return k.Tag < o.Tag
+}
@@ -0,0 +1,127 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package aggregate
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+func date(day, hour int) *time.Time {
+	t := time.Date(2013, time.July, day, hour, 0, 0, 0, time.UTC)
+	return &t
+}
+
+// July 2013: the 1st is a Monday, the 7th a Sunday.
+var entries = []toggl.TimeEntry{
+	{ProjectID: 1, UserID: 10, Start: date(1, 9), Duration: 3600, Billable: true, Tags: []string{"a", "b"}},
+	{ProjectID: 1, UserID: 11, Start: date(1, 13), Duration: 1800},
+	{ProjectID: 2, UserID: 10, Start: date(7, 9), Duration: 7200, Billable: true, Tags: []string{"a"}},
+	{ProjectID: 3, UserID: 10, Start: date(8, 9), Duration: 600},
+}
+
+var opts = &Options{
+	Projects: []toggl.Project{{ID: 1, ClientID: 100}, {ID: 2, ClientID: 100}, {ID: 3, ClientID: 200}},
+	Location: time.UTC,
+}
+
+func TestSum_grandTotal(t *testing.T) {
+	got := Sum(entries, opts)
+	want := []Total{{
+		Count:       4,
+		Duration:    3*time.Hour + 40*time.Minute,
+		Billable:    3 * time.Hour,
+		NonBillable: 40 * time.Minute,
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sum() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSum_clientAndProject(t *testing.T) {
+	got := Sum(entries, opts, Client, Project)
+	want := []Total{
+		{Key: Key{ClientID: 100, ProjectID: 1}, Count: 2, Duration: 90 * time.Minute, Billable: time.Hour, NonBillable: 30 * time.Minute},
+		{Key: Key{ClientID: 100, ProjectID: 2}, Count: 1, Duration: 2 * time.Hour, Billable: 2 * time.Hour},
+		{Key: Key{ClientID: 200, ProjectID: 3}, Count: 1, Duration: 10 * time.Minute, NonBillable: 10 * time.Minute},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sum(Client, Project) = %+v, want %+v", got, want)
+	}
+}
+
+func TestSum_tag(t *testing.T) {
+	got := Sum(entries, opts, Tag)
+	want := []Total{
+		{Count: 2, Duration: 40 * time.Minute, NonBillable: 40 * time.Minute},
+		{Key: Key{Tag: "a"}, Count: 2, Duration: 3 * time.Hour, Billable: 3 * time.Hour},
+		{Key: Key{Tag: "b"}, Count: 1, Duration: time.Hour, Billable: time.Hour},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sum(Tag) = %+v, want %+v", got, want)
+	}
+}
+
+func TestSum_week(t *testing.T) {
+	tests := []struct {
+		beginningOfWeek int
+		want            []Total
+	}{
+		// Monday
+		{1, []Total{
+			{Key: Key{Period: *date(1, 0)}, Count: 3, Duration: 3*time.Hour + 30*time.Minute, Billable: 3 * time.Hour, NonBillable: 30 * time.Minute},
+			{Key: Key{Period: *date(8, 0)}, Count: 1, Duration: 10 * time.Minute, NonBillable: 10 * time.Minute},
+		}},
+		// Sunday
+		{0, []Total{
+			{Key: Key{Period: time.Date(2013, time.June, 30, 0, 0, 0, 0, time.UTC)}, Count: 2, Duration: 90 * time.Minute, Billable: time.Hour, NonBillable: 30 * time.Minute},
+			{Key: Key{Period: *date(7, 0)}, Count: 2, Duration: 2*time.Hour + 10*time.Minute, Billable: 2 * time.Hour, NonBillable: 10 * time.Minute},
+		}},
+	}
+
+	for _, tt := range tests {
+		o := *opts
+		o.BeginningOfWeek = tt.beginningOfWeek
+		if got := Sum(entries, &o, Week); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Sum(Week) with week starting on %d = %+v, want %+v", tt.beginningOfWeek, got, tt.want)
+		}
+	}
+}
+
+func TestSum_dayAndUser(t *testing.T) {
+	got := Sum(entries[:2], opts, User, Day)
+	want := []Total{
+		{Key: Key{UserID: 10, Period: *date(1, 0)}, Count: 1, Duration: time.Hour, Billable: time.Hour},
+		{Key: Key{UserID: 11, Period: *date(1, 0)}, Count: 1, Duration: 30 * time.Minute, NonBillable: 30 * time.Minute},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sum(User, Day) = %+v, want %+v", got, want)
+	}
+}
+
+func TestSum_monthInLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+10", 10*60*60)
+	start := time.Date(2013, time.July, 31, 20, 0, 0, 0, time.UTC) // August 1st in loc
+
+	got := Sum([]toggl.TimeEntry{{Start: &start, Duration: 60}}, &Options{Location: loc}, Month)
+	want := time.Date(2013, time.August, 1, 0, 0, 0, 0, loc)
+	if len(got) != 1 || !got[0].Period.Equal(want) {
+		t.Errorf("Sum(Month) = %+v, want period %v", got, want)
+	}
+}
+
+func TestSum_running(t *testing.T) {
+	now := time.Date(2013, time.July, 1, 10, 0, 0, 0, time.UTC)
+	running := toggl.NewRunningTimeEntry(now.Add(-15 * time.Minute))
+
+	got := Sum([]toggl.TimeEntry{*running}, &Options{Now: now})
+	if len(got) != 1 || got[0].Duration != 15*time.Minute {
+		t.Errorf("Sum() of running entry = %+v, want 15m", got)
+	}
+}
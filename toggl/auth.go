@@ -0,0 +1,58 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggl
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Authenticator adds credentials to API requests.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/authentication.md
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// APITokenAuth authenticates requests with a user's API token. This is
+// what NewClient uses by default.
+type APITokenAuth struct {
+	Token string
+}
+
+// Authenticate sets the token as basic auth username with "api_token" as
+// password.
+func (a APITokenAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.Token, "api_token")
+	return nil
+}
+
+// BasicAuth authenticates requests with a user's email and password.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Authenticate sets the username and password as basic auth.
+func (a BasicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+// SessionAuth authenticates requests with a session cookie, as returned
+// by SessionsService.Create.
+type SessionAuth struct {
+	Cookie *http.Cookie
+}
+
+// Authenticate adds the session cookie to req.
+func (a SessionAuth) Authenticate(req *http.Request) error {
+	if a.Cookie == nil {
+		return errors.New("SessionAuth.Cookie cannot be nil")
+	}
+	req.AddCookie(a.Cookie)
+	return nil
+}
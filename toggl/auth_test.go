@@ -0,0 +1,73 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggl
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAPITokenAuth(t *testing.T) {
+	c := NewClient("token")
+	req, err := c.NewRequest("GET", "me", nil)
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+
+	// base64("token:api_token")
+	want := "Basic dG9rZW46YXBpX3Rva2Vu"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization header = %v, want %v", got, want)
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	c := NewClient("", WithAuthenticator(BasicAuth{Username: "user@example.com", Password: "secret"}))
+	req, err := c.NewRequest("GET", "me", nil)
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+
+	user, pass, ok := req.BasicAuth()
+	if !ok || user != "user@example.com" || pass != "secret" {
+		t.Errorf("BasicAuth() = %v, %v, %v, want user@example.com, secret, true", user, pass, ok)
+	}
+}
+
+func TestSessionAuth(t *testing.T) {
+	cookie := &http.Cookie{Name: SessionCookieName, Value: "s"}
+	c := NewClient("", WithAuthenticator(SessionAuth{Cookie: cookie}))
+	req, err := c.NewRequest("GET", "me", nil)
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+
+	if got, err := req.Cookie(SessionCookieName); err != nil || got.Value != "s" {
+		t.Errorf("session cookie = %v, %v, want value s", got, err)
+	}
+	if got := req.Header.Get("Authorization"); got != "" {
+		t.Errorf("Authorization header = %v, want none", got)
+	}
+}
+
+func TestSessionAuth_nilCookie(t *testing.T) {
+	c := NewClient("", WithAuthenticator(SessionAuth{}))
+	if _, err := c.NewRequest("GET", "me", nil); err == nil {
+		t.Errorf("NewRequest returned no error for SessionAuth without cookie")
+	}
+}
+
+func TestNilAuthenticator(t *testing.T) {
+	c := NewClient("token", WithAuthenticator(nil))
+	c.SetAuthenticator(nil)
+	req, err := c.NewRequest("GET", "me", nil)
+	if err != nil {
+		t.Fatalf("NewRequest returned error: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Basic dG9rZW46YXBpX3Rva2Vu" {
+		t.Errorf("Authorization header = %v, want the API token", got)
+	}
+}
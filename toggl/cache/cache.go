@@ -0,0 +1,473 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package cache keeps Toggl objects looked up through the API in a local
+Store, so that repeated lookups do not hit the network.
+
+A Cache wraps the clients, projects, tasks and time entries services of a
+toggl.Client:
+
+	c := cache.New(client, cache.NewMemoryStore())
+	p, err := c.Projects.Get(id) // fetched from Toggl
+	p, err = c.Projects.Get(id)  // served from the store
+
+Objects are fetched again once older than Cache.MaxAge. Updates and
+deletes made through the Cache are written through to the store, and an
+object is never replaced by a version with an older At timestamp.
+
+A Cache can be warmed from a snapshot, and kept up to date with the
+changes reported by a snapshot.Syncer:
+
+	err := c.Warm(syncer.Snapshot())
+	...
+	changes, err := syncer.Sync()
+	err = c.Apply(changes)
+*/
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+	"github.com/gedex/go-toggl/toggl/snapshot"
+)
+
+// DefaultMaxAge is the MaxAge of new caches.
+const DefaultMaxAge = 5 * time.Minute
+
+// Cache serves Toggl objects from a Store, fetching them from Toggl when
+// missing or stale.
+type Cache struct {
+	// How long a fetched object is served from the store. Objects never
+	// go stale if MaxAge is 0, and are then only refreshed by Warm, Apply
+	// and the updates made through the Cache.
+	MaxAge time.Duration
+
+	// Services wrapping the ones of the toggl.Client
+	Clients     *ClientsService
+	Projects    *ProjectsService
+	Tasks       *TasksService
+	TimeEntries *TimeEntriesService
+
+	client *toggl.Client
+	store  Store
+	now    func() time.Time
+}
+
+// New returns a Cache keeping in store the objects fetched through c.
+func New(c *toggl.Client, store Store) *Cache {
+	cache := &Cache{
+		MaxAge: DefaultMaxAge,
+		client: c,
+		store:  store,
+		now:    time.Now,
+	}
+	cache.Clients = &ClientsService{cache: cache}
+	cache.Projects = &ProjectsService{cache: cache}
+	cache.Tasks = &TasksService{cache: cache}
+	cache.TimeEntries = &TimeEntriesService{cache: cache}
+	return cache
+}
+
+// Invalidate removes an object from the store, so that the next lookup
+// fetches it from Toggl.
+func (c *Cache) Invalidate(kind Kind, id int) error {
+	return c.store.Delete(kind, id)
+}
+
+// Warm stores all clients, projects, tasks and time entries of snap.
+func (c *Cache) Warm(snap *snapshot.Snapshot) error {
+	b := c.batch()
+	for id, wc := range snap.Clients {
+		b.add(KindClient, id, wc.At, wc)
+	}
+	for id, p := range snap.Projects {
+		b.add(KindProject, id, p.At, p)
+	}
+	for id, t := range snap.Tasks {
+		b.add(KindTask, id, t.At, t)
+	}
+	for id, te := range snap.TimeEntries {
+		b.add(KindTimeEntry, id, te.At, te)
+	}
+	return b.write()
+}
+
+// Apply stores the objects created and updated by a sync, and removes the
+// deleted ones.
+func (c *Cache) Apply(ch *snapshot.Changes) error {
+	b := c.batch()
+	for _, list := range [][]toggl.WorkspaceClient{ch.Clients.Created, ch.Clients.Updated} {
+		for _, wc := range list {
+			b.add(KindClient, wc.ID, wc.At, wc)
+		}
+	}
+	for _, list := range [][]toggl.Project{ch.Projects.Created, ch.Projects.Updated} {
+		for _, p := range list {
+			b.add(KindProject, p.ID, p.At, p)
+		}
+	}
+	for _, list := range [][]toggl.Task{ch.Tasks.Created, ch.Tasks.Updated} {
+		for _, t := range list {
+			b.add(KindTask, t.ID, t.At, t)
+		}
+	}
+	for _, list := range [][]toggl.TimeEntry{ch.TimeEntries.Created, ch.TimeEntries.Updated} {
+		for _, te := range list {
+			b.add(KindTimeEntry, te.ID, te.At, te)
+		}
+	}
+	if err := b.write(); err != nil {
+		return err
+	}
+
+	for _, wc := range ch.Clients.Deleted {
+		if err := c.store.Delete(KindClient, wc.ID); err != nil {
+			return err
+		}
+	}
+	for _, p := range ch.Projects.Deleted {
+		if err := c.store.Delete(KindProject, p.ID); err != nil {
+			return err
+		}
+	}
+	for _, t := range ch.Tasks.Deleted {
+		if err := c.store.Delete(KindTask, t.ID); err != nil {
+			return err
+		}
+	}
+	for _, te := range ch.TimeEntries.Deleted {
+		if err := c.store.Delete(KindTimeEntry, te.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup decodes the stored object into v, and reports whether it was
+// found and fresh.
+func (c *Cache) lookup(kind Kind, id int, v interface{}) (bool, error) {
+	e, err := c.store.Get(kind, id)
+	if err != nil || e == nil {
+		return false, err
+	}
+	if c.MaxAge > 0 && c.now().Sub(e.Fetched) >= c.MaxAge {
+		return false, nil
+	}
+	return true, json.Unmarshal(e.Data, v)
+}
+
+// put stores v, which must be a pointer, unless the store holds a version
+// modified after at. That version is then decoded into v instead, so that
+// callers return the object kept by the store.
+func (c *Cache) put(kind Kind, id int, at *time.Time, v interface{}) error {
+	e, kept, err := c.entry(kind, id, at, v)
+	if err != nil {
+		return err
+	}
+	if err := c.store.Put(kind, id, e); err != nil {
+		return err
+	}
+	if !kept {
+		return nil
+	}
+	rv := reflect.ValueOf(v).Elem()
+	rv.Set(reflect.Zero(rv.Type()))
+	return json.Unmarshal(e.Data, v)
+}
+
+// entry returns the entry to store for v. If the store holds a version
+// modified after at, that version is kept and only marked as fetched now,
+// which is reported by kept.
+func (c *Cache) entry(kind Kind, id int, at *time.Time, v interface{}) (e *Entry, kept bool, err error) {
+	e = &Entry{Fetched: c.now()}
+	if at != nil {
+		e.At = *at
+	}
+
+	old, err := c.store.Get(kind, id)
+	if err != nil {
+		return nil, false, err
+	}
+	if old != nil && old.At.After(e.At) {
+		old.Fetched = e.Fetched
+		return old, true, nil
+	}
+
+	if e.Data, err = json.Marshal(v); err != nil {
+		return nil, false, err
+	}
+	return e, false, nil
+}
+
+// batch collects entries written with a single PutMany per kind.
+type batch struct {
+	c       *Cache
+	entries map[Kind]map[int]*Entry
+	err     error
+}
+
+func (c *Cache) batch() *batch {
+	return &batch{c: c, entries: make(map[Kind]map[int]*Entry)}
+}
+
+// add adds v to the batch, like Cache.put.
+func (b *batch) add(kind Kind, id int, at *time.Time, v interface{}) {
+	if b.err != nil {
+		return
+	}
+	e, _, err := b.c.entry(kind, id, at, v)
+	if err != nil {
+		b.err = err
+		return
+	}
+	if b.entries[kind] == nil {
+		b.entries[kind] = make(map[int]*Entry)
+	}
+	b.entries[kind][id] = e
+}
+
+// write stores the entries of the batch.
+func (b *batch) write() error {
+	if b.err != nil {
+		return b.err
+	}
+	for kind, entries := range b.entries {
+		if err := b.c.store.PutMany(kind, entries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// failed forgets objects Toggl reports as not found, and returns err.
+func (c *Cache) failed(kind Kind, id int, err error) error {
+	if toggl.IsNotFound(err) {
+		if err := c.store.Delete(kind, id); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// ClientsService is a cached toggl.ClientsService.
+type ClientsService struct {
+	cache *Cache
+}
+
+// Get returns a client.
+func (s *ClientsService) Get(id int) (*toggl.WorkspaceClient, error) {
+	return s.GetContext(context.Background(), id)
+}
+
+// GetContext is like Get, but with the provided context.
+func (s *ClientsService) GetContext(ctx context.Context, id int) (*toggl.WorkspaceClient, error) {
+	wc := new(toggl.WorkspaceClient)
+	if ok, err := s.cache.lookup(KindClient, id, wc); ok || err != nil {
+		return wc, err
+	}
+
+	wc, err := s.cache.client.Clients.GetContext(ctx, id)
+	if err != nil {
+		return nil, s.cache.failed(KindClient, id, err)
+	}
+	if wc == nil {
+		return nil, nil
+	}
+	return wc, s.cache.put(KindClient, id, wc.At, wc)
+}
+
+// Update updates a client and stores the result.
+func (s *ClientsService) Update(wc *toggl.WorkspaceClient) (*toggl.WorkspaceClient, error) {
+	return s.UpdateContext(context.Background(), wc)
+}
+
+// UpdateContext is like Update, but with the provided context.
+func (s *ClientsService) UpdateContext(ctx context.Context, wc *toggl.WorkspaceClient) (*toggl.WorkspaceClient, error) {
+	wc, err := s.cache.client.Clients.UpdateContext(ctx, wc)
+	if err != nil || wc == nil {
+		return wc, err
+	}
+	return wc, s.cache.put(KindClient, wc.ID, wc.At, wc)
+}
+
+// Delete deletes a client and removes it from the store.
+func (s *ClientsService) Delete(id int) error {
+	return s.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is like Delete, but with the provided context.
+func (s *ClientsService) DeleteContext(ctx context.Context, id int) error {
+	if err := s.cache.client.Clients.DeleteContext(ctx, id); err != nil {
+		return err
+	}
+	return s.cache.store.Delete(KindClient, id)
+}
+
+// ProjectsService is a cached toggl.ProjectsService.
+type ProjectsService struct {
+	cache *Cache
+}
+
+// Get returns a project.
+func (s *ProjectsService) Get(id int) (*toggl.Project, error) {
+	return s.GetContext(context.Background(), id)
+}
+
+// GetContext is like Get, but with the provided context.
+func (s *ProjectsService) GetContext(ctx context.Context, id int) (*toggl.Project, error) {
+	p := new(toggl.Project)
+	if ok, err := s.cache.lookup(KindProject, id, p); ok || err != nil {
+		return p, err
+	}
+
+	p, err := s.cache.client.Projects.GetContext(ctx, id)
+	if err != nil {
+		return nil, s.cache.failed(KindProject, id, err)
+	}
+	if p == nil {
+		return nil, nil
+	}
+	return p, s.cache.put(KindProject, id, p.At, p)
+}
+
+// Update updates a project and stores the result.
+func (s *ProjectsService) Update(p *toggl.Project) (*toggl.Project, error) {
+	return s.UpdateContext(context.Background(), p)
+}
+
+// UpdateContext is like Update, but with the provided context.
+func (s *ProjectsService) UpdateContext(ctx context.Context, p *toggl.Project) (*toggl.Project, error) {
+	p, err := s.cache.client.Projects.UpdateContext(ctx, p)
+	if err != nil || p == nil {
+		return p, err
+	}
+	return p, s.cache.put(KindProject, p.ID, p.At, p)
+}
+
+// Delete deletes a project and removes it from the store.
+func (s *ProjectsService) Delete(id int) error {
+	return s.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is like Delete, but with the provided context.
+func (s *ProjectsService) DeleteContext(ctx context.Context, id int) error {
+	if err := s.cache.client.Projects.DeleteContext(ctx, id); err != nil {
+		return err
+	}
+	return s.cache.store.Delete(KindProject, id)
+}
+
+// TasksService is a cached toggl.TasksService.
+type TasksService struct {
+	cache *Cache
+}
+
+// Get returns a task.
+func (s *TasksService) Get(id int) (*toggl.Task, error) {
+	return s.GetContext(context.Background(), id)
+}
+
+// GetContext is like Get, but with the provided context.
+func (s *TasksService) GetContext(ctx context.Context, id int) (*toggl.Task, error) {
+	t := new(toggl.Task)
+	if ok, err := s.cache.lookup(KindTask, id, t); ok || err != nil {
+		return t, err
+	}
+
+	t, err := s.cache.client.Tasks.GetContext(ctx, id)
+	if err != nil {
+		return nil, s.cache.failed(KindTask, id, err)
+	}
+	if t == nil {
+		return nil, nil
+	}
+	return t, s.cache.put(KindTask, id, t.At, t)
+}
+
+// Update updates a task and stores the result.
+func (s *TasksService) Update(t *toggl.Task) (*toggl.Task, error) {
+	return s.UpdateContext(context.Background(), t)
+}
+
+// UpdateContext is like Update, but with the provided context.
+func (s *TasksService) UpdateContext(ctx context.Context, t *toggl.Task) (*toggl.Task, error) {
+	t, err := s.cache.client.Tasks.UpdateContext(ctx, t)
+	if err != nil || t == nil {
+		return t, err
+	}
+	return t, s.cache.put(KindTask, t.ID, t.At, t)
+}
+
+// Delete deletes a task and removes it from the store.
+func (s *TasksService) Delete(id int) error {
+	return s.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is like Delete, but with the provided context.
+func (s *TasksService) DeleteContext(ctx context.Context, id int) error {
+	if err := s.cache.client.Tasks.DeleteContext(ctx, id); err != nil {
+		return err
+	}
+	return s.cache.store.Delete(KindTask, id)
+}
+
+// TimeEntriesService is a cached toggl.TimeEntriesService.
+type TimeEntriesService struct {
+	cache *Cache
+}
+
+// Get returns a time entry.
+func (s *TimeEntriesService) Get(id int) (*toggl.TimeEntry, error) {
+	return s.GetContext(context.Background(), id)
+}
+
+// GetContext is like Get, but with the provided context.
+func (s *TimeEntriesService) GetContext(ctx context.Context, id int) (*toggl.TimeEntry, error) {
+	te := new(toggl.TimeEntry)
+	if ok, err := s.cache.lookup(KindTimeEntry, id, te); ok || err != nil {
+		return te, err
+	}
+
+	te, err := s.cache.client.TimeEntries.GetContext(ctx, id)
+	if err != nil {
+		return nil, s.cache.failed(KindTimeEntry, id, err)
+	}
+	if te == nil {
+		return nil, nil
+	}
+	return te, s.cache.put(KindTimeEntry, id, te.At, te)
+}
+
+// Update updates a time entry and stores the result.
+func (s *TimeEntriesService) Update(te *toggl.TimeEntry) (*toggl.TimeEntry, error) {
+	return s.UpdateContext(context.Background(), te)
+}
+
+// UpdateContext is like Update, but with the provided context.
+func (s *TimeEntriesService) UpdateContext(ctx context.Context, te *toggl.TimeEntry) (*toggl.TimeEntry, error) {
+	te, err := s.cache.client.TimeEntries.UpdateContext(ctx, te)
+	if err != nil || te == nil {
+		return te, err
+	}
+	return te, s.cache.put(KindTimeEntry, te.ID, te.At, te)
+}
+
+// Delete deletes a time entry and removes it from the store.
+func (s *TimeEntriesService) Delete(id int) error {
+	return s.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is like Delete, but with the provided context.
+func (s *TimeEntriesService) DeleteContext(ctx context.Context, id int) error {
+	if err := s.cache.client.TimeEntries.DeleteContext(ctx, id); err != nil {
+		return err
+	}
+	return s.cache.store.Delete(KindTimeEntry, id)
+}
@@ -0,0 +1,164 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+	"github.com/gedex/go-toggl/toggl/snapshot"
+	"github.com/gedex/go-toggl/toggl/toggltest"
+)
+
+func setup() (*toggltest.Server, *Cache, *time.Time) {
+	s := toggltest.NewServer()
+	now := time.Date(2013, time.July, 1, 12, 0, 0, 0, time.UTC)
+	s.SetClock(func() time.Time { return now })
+	c := New(s.Client(), NewMemoryStore())
+	c.now = func() time.Time { return now }
+	return s, c, &now
+}
+
+func TestCache_Get(t *testing.T) {
+	s, c, now := setup()
+	defer s.Close()
+	p := s.AddProject(toggl.Project{WorkspaceID: s.User().DefautWID, Name: "Website"})
+
+	for i := 0; i < 2; i++ {
+		got, err := c.Projects.Get(p.ID)
+		if err != nil {
+			t.Fatalf("Projects.Get returned error: %v", err)
+		}
+		if !reflect.DeepEqual(*got, p) {
+			t.Errorf("Projects.Get returned %+v, want %+v", got, p)
+		}
+	}
+	if n := len(s.Requests()); n != 1 {
+		t.Errorf("Projects.Get sent %d requests, want 1", n)
+	}
+
+	*now = now.Add(DefaultMaxAge)
+	if _, err := c.Projects.Get(p.ID); err != nil {
+		t.Fatalf("Projects.Get returned error: %v", err)
+	}
+	if n := len(s.Requests()); n != 2 {
+		t.Errorf("Projects.Get of stale project sent %d requests, want 2", n)
+	}
+
+	if _, err := c.Tasks.Get(12345); !toggl.IsNotFound(err) {
+		t.Errorf("Tasks.Get of missing task returned %v, want not found", err)
+	}
+}
+
+func TestCache_writeThrough(t *testing.T) {
+	s, c, now := setup()
+	defer s.Close()
+	wc := s.AddClient(toggl.WorkspaceClient{WorkspaceID: s.User().DefautWID, Name: "Acme"})
+
+	*now = now.Add(time.Minute)
+	if _, err := c.Clients.Update(&toggl.WorkspaceClient{ID: wc.ID, Name: "Acme Corp"}); err != nil {
+		t.Fatalf("Clients.Update returned error: %v", err)
+	}
+	got, err := c.Clients.Get(wc.ID)
+	if err != nil || got.Name != "Acme Corp" {
+		t.Errorf("Clients.Get after Update returned %+v, %v", got, err)
+	}
+	if n := len(s.Requests()); n != 1 {
+		t.Errorf("Clients.Get after Update sent %d requests, want 1", n)
+	}
+
+	p := s.AddProject(toggl.Project{WorkspaceID: s.User().DefautWID, Name: "Website"})
+	if _, err := c.Projects.Get(p.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Projects.Delete(p.ID); err != nil {
+		t.Fatalf("Projects.Delete returned error: %v", err)
+	}
+	if _, err := c.Projects.Get(p.ID); !toggl.IsNotFound(err) {
+		t.Errorf("Projects.Get after Delete returned %v, want not found", err)
+	}
+
+	if err := c.Clients.Delete(wc.ID); err != nil {
+		t.Fatalf("Clients.Delete returned error: %v", err)
+	}
+	if _, err := c.Clients.Get(wc.ID); !toggl.IsNotFound(err) {
+		t.Errorf("Clients.Get after Delete returned %v, want not found", err)
+	}
+}
+
+func TestCache_put_keepsNewer(t *testing.T) {
+	_, c, now := setup()
+	earlier, later := *now, now.Add(time.Minute)
+
+	if err := c.put(KindTask, 1, &later, &toggl.Task{ID: 1, Name: "new", At: &later}); err != nil {
+		t.Fatal(err)
+	}
+	old := &toggl.Task{ID: 1, Name: "old", Active: true, At: now}
+	if err := c.put(KindTask, 1, now, old); err != nil {
+		t.Fatal(err)
+	}
+	if old.Name != "new" || old.Active {
+		t.Errorf("put left %+v, want the newer task", old)
+	}
+
+	var task toggl.Task
+	if ok, err := c.lookup(KindTask, 1, &task); !ok || err != nil || task.Name != "new" {
+		t.Errorf("lookup returned %+v, %v, %v, want the newer task", task, ok, err)
+	}
+
+	// The newer task is kept but counts as fetched again.
+	*now = now.Add(DefaultMaxAge)
+	if err := c.put(KindTask, 1, &earlier, &toggl.Task{ID: 1, Name: "old", At: &earlier}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.lookup(KindTask, 1, &task); !ok || err != nil || task.Name != "new" {
+		t.Errorf("lookup after MaxAge returned %+v, %v, %v, want the newer task", task, ok, err)
+	}
+}
+
+func TestCache_Warm(t *testing.T) {
+	s, c, _ := setup()
+	defer s.Close()
+	wid := s.User().DefautWID
+	p := s.AddProject(toggl.Project{WorkspaceID: wid, Name: "Website"})
+	task := s.AddTask(toggl.Task{WorkspaceID: wid, ProjectID: p.ID, Name: "QA"})
+
+	syncer := snapshot.NewSyncer(s.Client(), nil)
+	if _, err := syncer.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Warm(syncer.Snapshot()); err != nil {
+		t.Fatalf("Warm returned error: %v", err)
+	}
+	n := len(s.Requests())
+
+	if got, err := c.Projects.Get(p.ID); err != nil || got.Name != "Website" {
+		t.Errorf("Projects.Get returned %+v, %v", got, err)
+	}
+	if got, err := c.Tasks.Get(task.ID); err != nil || got.Name != "QA" {
+		t.Errorf("Tasks.Get returned %+v, %v", got, err)
+	}
+	if len(s.Requests()) != n {
+		t.Errorf("Get of warmed objects sent requests: %v", s.Requests()[n:])
+	}
+
+	if err := s.Client().Tasks.Delete(task.ID); err != nil {
+		t.Fatal(err)
+	}
+	ch, err := syncer.Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Apply(ch); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	var got toggl.Task
+	if ok, _ := c.lookup(KindTask, task.ID, &got); ok {
+		t.Errorf("Apply kept deleted task %+v", got)
+	}
+}
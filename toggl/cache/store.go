@@ -0,0 +1,228 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl/internal/fileutil"
+)
+
+// Kind names a type of Toggl object kept in a Store.
+type Kind string
+
+// Kinds of cached objects.
+const (
+	KindClient    Kind = "clients"
+	KindProject   Kind = "projects"
+	KindTask      Kind = "tasks"
+	KindTimeEntry Kind = "time_entries"
+)
+
+// Entry is an object kept in a Store.
+type Entry struct {
+	// JSON encoding of the object
+	Data json.RawMessage `json:"data"`
+
+	// Last modification time of the object, zero if unknown
+	At time.Time `json:"at"`
+
+	// When the object was fetched from Toggl
+	Fetched time.Time `json:"fetched"`
+}
+
+// Store keeps cached objects by kind and ID. Implementations must be safe
+// for concurrent use.
+type Store interface {
+	// Get returns the entry of an object, or nil if it is not stored.
+	Get(kind Kind, id int) (*Entry, error)
+
+	// Put stores the entry of an object, replacing any previous one.
+	Put(kind Kind, id int, e *Entry) error
+
+	// PutMany stores the entries of several objects of a kind by ID, like
+	// Put but in a single write.
+	PutMany(kind Kind, entries map[int]*Entry) error
+
+	// Delete removes an object. Deleting a missing object is not an error.
+	Delete(kind Kind, id int) error
+
+	// Clear removes all objects.
+	Clear() error
+}
+
+// entries holds stored entries by kind and ID.
+type entries map[Kind]map[int]*Entry
+
+func (m entries) get(kind Kind, id int) *Entry {
+	e := m[kind][id]
+	if e == nil {
+		return nil
+	}
+	c := *e
+	return &c
+}
+
+func (m entries) put(kind Kind, id int, e *Entry) {
+	if m[kind] == nil {
+		m[kind] = make(map[int]*Entry)
+	}
+	c := *e
+	m[kind][id] = &c
+}
+
+// MemoryStore is a Store keeping objects in memory.
+type MemoryStore struct {
+	mu sync.Mutex
+	m  entries
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{m: make(entries)}
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(kind Kind, id int) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.m.get(kind, id), nil
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(kind Kind, id int, e *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m.put(kind, id, e)
+	return nil
+}
+
+// PutMany implements Store.
+func (s *MemoryStore) PutMany(kind Kind, entries map[int]*Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, e := range entries {
+		s.m.put(kind, id, e)
+	}
+	return nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(kind Kind, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m[kind], id)
+	return nil
+}
+
+// Clear implements Store.
+func (s *MemoryStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m = make(entries)
+	return nil
+}
+
+// FileStore is a Store keeping objects in a JSON file, so that they
+// survive restarts. Objects are held in memory and every change rewrites
+// the file, which suits caches of a few thousand objects. PutMany stores
+// many objects with a single write.
+type FileStore struct {
+	path string
+
+	mu sync.Mutex
+	m  entries
+}
+
+// OpenFileStore returns a FileStore reading and writing the file at path.
+// The file is created on the first change if it does not exist.
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, m: make(entries)}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[Kind]map[string]*Entry
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	for kind, byID := range m {
+		for k, e := range byID {
+			id, err := strconv.Atoi(k)
+			if err != nil {
+				return nil, err
+			}
+			s.m.put(kind, id, e)
+		}
+	}
+	return s, nil
+}
+
+// Get implements Store.
+func (s *FileStore) Get(kind Kind, id int) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.m.get(kind, id), nil
+}
+
+// Put implements Store.
+func (s *FileStore) Put(kind Kind, id int, e *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m.put(kind, id, e)
+	return s.save()
+}
+
+// PutMany implements Store.
+func (s *FileStore) PutMany(kind Kind, entries map[int]*Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, e := range entries {
+		s.m.put(kind, id, e)
+	}
+	return s.save()
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(kind Kind, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.m[kind][id]; !ok {
+		return nil
+	}
+	delete(s.m[kind], id)
+	return s.save()
+}
+
+// Clear implements Store.
+func (s *FileStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m = make(entries)
+	return s.save()
+}
+
+// save writes the objects to the store file.
+func (s *FileStore) save() error {
+	b, err := json.Marshal(s.m)
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFile(s.path, b)
+}
@@ -0,0 +1,90 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testStore(t *testing.T, s Store) {
+	e := &Entry{
+		Data:    json.RawMessage(`{"id":1}`),
+		At:      time.Date(2013, time.July, 1, 12, 0, 0, 0, time.UTC),
+		Fetched: time.Date(2013, time.July, 1, 12, 5, 0, 0, time.UTC),
+	}
+	if err := s.Put(KindProject, 1, e); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+	if got, err := s.Get(KindProject, 1); err != nil || !reflect.DeepEqual(got, e) {
+		t.Errorf("Get returned %+v, %v, want %+v", got, err, e)
+	}
+	if got, err := s.Get(KindTask, 1); err != nil || got != nil {
+		t.Errorf("Get of other kind returned %+v, %v, want nil", got, err)
+	}
+
+	if err := s.Delete(KindProject, 1); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if got, err := s.Get(KindProject, 1); err != nil || got != nil {
+		t.Errorf("Get after Delete returned %+v, %v, want nil", got, err)
+	}
+	if err := s.Delete(KindProject, 1); err != nil {
+		t.Errorf("Delete of missing entry returned error: %v", err)
+	}
+
+	if err := s.PutMany(KindTask, map[int]*Entry{2: e, 3: e}); err != nil {
+		t.Fatalf("PutMany returned error: %v", err)
+	}
+	for _, id := range []int{2, 3} {
+		if got, err := s.Get(KindTask, id); err != nil || !reflect.DeepEqual(got, e) {
+			t.Errorf("Get after PutMany returned %+v, %v, want %+v", got, err, e)
+		}
+	}
+
+	if err := s.Clear(); err != nil {
+		t.Fatalf("Clear returned error: %v", err)
+	}
+	if got, _ := s.Get(KindTask, 2); got != nil {
+		t.Errorf("Get after Clear returned %+v, want nil", got)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := os.MkdirTemp("", "toggl-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cache.json")
+
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore returned error: %v", err)
+	}
+	testStore(t, s)
+
+	e := &Entry{Data: json.RawMessage(`{"id":3}`), At: time.Date(2013, time.July, 1, 12, 0, 0, 0, time.UTC)}
+	if err := s.Put(KindTimeEntry, 3, e); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore of existing file returned error: %v", err)
+	}
+	if got, err := s.Get(KindTimeEntry, 3); err != nil || !reflect.DeepEqual(got, e) {
+		t.Errorf("Get of reopened store returned %+v, %v, want %+v", got, err, e)
+	}
+}
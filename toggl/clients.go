@@ -6,6 +6,7 @@
 package toggl
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -21,13 +22,14 @@ type ClientsService struct {
 
 // WorkspaceClient represents client of user's workspace.
 type WorkspaceClient struct {
-	ID          int        `json:"id,omitempty"`
-	WorkspaceID int        `json:"wid,omitempty"`
-	Name        string     `json:"name,omitempty"`
-	Notes       string     `json:"notes,omitempty"`
-	HourlyRate  float64    `json:"hrate,omitempty"`
-	Currency    string     `json:"cur,omitempty"`
-	At          *time.Time `json:"at,omitempty"` // indicates the time client was last updated
+	ID              int        `json:"id,omitempty"`
+	WorkspaceID     int        `json:"wid,omitempty"`
+	Name            string     `json:"name,omitempty"`
+	Notes           string     `json:"notes,omitempty"`
+	HourlyRate      float64    `json:"hrate,omitempty"`
+	Currency        string     `json:"cur,omitempty"`
+	At              *time.Time `json:"at,omitempty"`                // indicates the time client was last updated
+	ServerDeletedAt *time.Time `json:"server_deleted_at,omitempty"` // set once the client is deleted
 }
 
 // WorkspaceClientResponse acts as a response wrapper where response returns
@@ -45,9 +47,14 @@ type WorkspaceClientCreate struct {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/clients.md#get-clients-visible-to-user
 func (s *ClientsService) List() ([]WorkspaceClient, error) {
+	return s.ListContext(context.Background())
+}
+
+// ListContext is like List, but with the provided context.
+func (s *ClientsService) ListContext(ctx context.Context) ([]WorkspaceClient, error) {
 	u := "clients"
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -62,9 +69,14 @@ func (s *ClientsService) List() ([]WorkspaceClient, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/clients.md#get-client-projects
 func (s *ClientsService) ListClientProjects(id int) ([]Project, error) {
+	return s.ListClientProjectsContext(context.Background(), id)
+}
+
+// ListClientProjectsContext is like ListClientProjects, but with the provided context.
+func (s *ClientsService) ListClientProjectsContext(ctx context.Context, id int) ([]Project, error) {
 	u := fmt.Sprintf("clients/%v/projects", id)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -79,8 +91,13 @@ func (s *ClientsService) ListClientProjects(id int) ([]Project, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/clients.md#get-client-details
 func (s *ClientsService) Get(id int) (*WorkspaceClient, error) {
+	return s.GetContext(context.Background(), id)
+}
+
+// GetContext is like Get, but with the provided context.
+func (s *ClientsService) GetContext(ctx context.Context, id int) (*WorkspaceClient, error) {
 	u := fmt.Sprintf("clients/%v", id)
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -95,9 +112,14 @@ func (s *ClientsService) Get(id int) (*WorkspaceClient, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/clients.md#create-a-client
 func (s *ClientsService) Create(wc *WorkspaceClient) (*WorkspaceClient, error) {
+	return s.CreateContext(context.Background(), wc)
+}
+
+// CreateContext is like Create, but with the provided context.
+func (s *ClientsService) CreateContext(ctx context.Context, wc *WorkspaceClient) (*WorkspaceClient, error) {
 	u := "clients"
 	wcc := &WorkspaceClientCreate{wc}
-	req, err := s.client.NewRequest("POST", u, wcc)
+	req, err := s.client.NewRequestContext(ctx, "POST", u, wcc)
 	if err != nil {
 		return nil, err
 	}
@@ -112,6 +134,11 @@ func (s *ClientsService) Create(wc *WorkspaceClient) (*WorkspaceClient, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/clients.md#update-a-client
 func (s *ClientsService) Update(wc *WorkspaceClient) (*WorkspaceClient, error) {
+	return s.UpdateContext(context.Background(), wc)
+}
+
+// UpdateContext is like Update, but with the provided context.
+func (s *ClientsService) UpdateContext(ctx context.Context, wc *WorkspaceClient) (*WorkspaceClient, error) {
 	if wc == nil {
 		return nil, errors.New("WorkspaceClient cannot be nil")
 	}
@@ -122,7 +149,7 @@ func (s *ClientsService) Update(wc *WorkspaceClient) (*WorkspaceClient, error) {
 	u := fmt.Sprintf("clients/%v", wc.ID)
 
 	wcc := &WorkspaceClientCreate{wc}
-	req, err := s.client.NewRequest("PUT", u, wcc)
+	req, err := s.client.NewRequestContext(ctx, "PUT", u, wcc)
 	if err != nil {
 		return nil, err
 	}
@@ -133,12 +160,32 @@ func (s *ClientsService) Update(wc *WorkspaceClient) (*WorkspaceClient, error) {
 	return data.Data, err
 }
 
+// UpdateChecked updates a client, unless it changed since wc was fetched.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/clients.md#update-a-client
+func (s *ClientsService) UpdateChecked(wc *WorkspaceClient, merge ClientMergeFunc) (*WorkspaceClient, error) {
+	return s.UpdateCheckedContext(context.Background(), wc, merge)
+}
+
+// UpdateCheckedContext is like UpdateChecked, but with the provided context.
+func (s *ClientsService) UpdateCheckedContext(ctx context.Context, wc *WorkspaceClient, merge ClientMergeFunc) (*WorkspaceClient, error) {
+	return updateChecked("WorkspaceClient", wc, merge,
+		func(x *WorkspaceClient) (*int, *time.Time) { return &x.ID, x.At },
+		func(x *WorkspaceClient) (*WorkspaceClient, error) { return s.GetContext(ctx, x.ID) },
+		func(x *WorkspaceClient) (*WorkspaceClient, error) { return s.UpdateContext(ctx, x) })
+}
+
 // Delete a client.
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/clients.md#delete-a-client
 func (s *ClientsService) Delete(id int) error {
+	return s.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is like Delete, but with the provided context.
+func (s *ClientsService) DeleteContext(ctx context.Context, id int) error {
 	u := fmt.Sprintf("clients/%v", id)
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, nil)
 	if err != nil {
 		return err
 	}
@@ -7,10 +7,12 @@ package toggl
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestClientsService_List(t *testing.T) {
@@ -131,6 +133,28 @@ func TestClientsService_Update(t *testing.T) {
 	}
 }
 
+func TestClientsService_UpdateChecked(t *testing.T) {
+	setup()
+	defer teardown()
+
+	at := time.Date(2013, time.July, 1, 11, 0, 0, 0, time.UTC)
+	mux.HandleFunc("/clients/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data":{"id": 1, "name": "theirs", "at": "2013-07-01T12:00:00Z"}}`)
+	})
+
+	abort := errors.New("abort")
+	_, err := client.Clients.UpdateChecked(&WorkspaceClient{ID: 1, At: &at}, func(mine, theirs *WorkspaceClient, err *ConflictError) (*WorkspaceClient, error) {
+		if theirs.Name != "theirs" || err.Kind != "WorkspaceClient" {
+			t.Errorf("merge called with %+v, %v", theirs, err)
+		}
+		return nil, abort
+	})
+	if err != abort {
+		t.Errorf("Clients.UpdateChecked returned %v, want merge error", err)
+	}
+}
+
 func TestClientsService_Delete(t *testing.T) {
 	setup()
 	defer teardown()
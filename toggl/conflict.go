@@ -0,0 +1,112 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggl
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ConflictError is returned by the UpdateChecked methods when the object
+// was changed on Toggl after the caller's copy was fetched, as told by
+// their At timestamps, and no merge function resolved it. Toggl has no
+// conditional updates, so a change made between the check and the update
+// is still overwritten.
+type ConflictError struct {
+	// Kind of object, such as "Project"
+	Kind string
+	ID   int
+
+	// Caller's copy and current version of the object, both of the same
+	// type: *Project, *Task, *WorkspaceClient or *ProjectUser
+	Mine   interface{}
+	Theirs interface{}
+
+	// Last modification times of both versions
+	MineAt   time.Time
+	TheirsAt time.Time
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %d was modified at %v, after %v",
+		e.Kind, e.ID, e.TheirsAt.Format(time.RFC3339), e.MineAt.Format(time.RFC3339))
+}
+
+// IsConflict reports whether err is a *ConflictError.
+func IsConflict(err error) bool {
+	var e *ConflictError
+	return errors.As(err, &e)
+}
+
+// Merge functions resolve a conflict detected by UpdateChecked. They are
+// given the caller's copy and the current version of the object, and
+// return the object to update it with, or an error to abort the update,
+// such as the *ConflictError passed along. Without a merge function,
+// UpdateChecked returns the *ConflictError.
+type (
+	ClientMergeFunc      func(mine, theirs *WorkspaceClient, err *ConflictError) (*WorkspaceClient, error)
+	ProjectMergeFunc     func(mine, theirs *Project, err *ConflictError) (*Project, error)
+	TaskMergeFunc        func(mine, theirs *Task, err *ConflictError) (*Task, error)
+	ProjectUserMergeFunc func(mine, theirs *ProjectUser, err *ConflictError) (*ProjectUser, error)
+)
+
+// conflict returns a *ConflictError if theirsAt differs from mineAt, which
+// must not be nil.
+func conflict(kind string, id int, mine interface{}, mineAt *time.Time, theirs interface{}, theirsAt *time.Time) *ConflictError {
+	if theirsAt != nil && theirsAt.Equal(*mineAt) {
+		return nil
+	}
+	e := &ConflictError{Kind: kind, ID: id, Mine: mine, Theirs: theirs, MineAt: *mineAt}
+	if theirsAt != nil {
+		e.TheirsAt = *theirsAt
+	}
+	return e
+}
+
+// updateChecked implements the UpdateChecked methods. It gets the current
+// version of mine with get and compares their At timestamps. If they
+// differ, merge is called to resolve the conflict, or a *ConflictError is
+// returned if merge is nil. The resulting object is then saved with update;
+// as Toggl has no conditional updates, a change made between get and update
+// is overwritten. fields returns the ID and At fields of an object.
+func updateChecked[T any](kind string, mine *T, merge func(mine, theirs *T, err *ConflictError) (*T, error),
+	fields func(*T) (*int, *time.Time), get, update func(*T) (*T, error)) (*T, error) {
+	if mine == nil {
+		return nil, fmt.Errorf("%s cannot be nil", kind)
+	}
+	id, at := fields(mine)
+	if *id <= 0 {
+		return nil, fmt.Errorf("Invalid %s.ID", kind)
+	}
+	if at == nil {
+		return nil, fmt.Errorf("%s.At cannot be nil", kind)
+	}
+
+	current, err := get(mine)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("%s not returned", kind)
+	}
+	currentID, currentAt := fields(current)
+	if c := conflict(kind, *id, mine, at, current, currentAt); c != nil {
+		if merge == nil {
+			return nil, c
+		}
+		if mine, err = merge(mine, current, c); err != nil {
+			return nil, err
+		}
+		if mine == nil {
+			return nil, fmt.Errorf("%s cannot be nil", kind)
+		}
+		id, _ = fields(mine)
+		*id = *currentID
+	}
+
+	return update(mine)
+}
@@ -0,0 +1,233 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package csvio reads and writes Toggl time entries as CSV, in the columns of
+Toggl's own detailed report export.
+
+Exporting resolves project, client, task and user names through Names:
+
+	names, err := csvio.LoadNames(ctx, c, wid)
+	if err != nil {
+		return err
+	}
+	w := csvio.NewWriter(f, names)
+	err = w.WriteAll(entries)
+
+Importing turns each row into a time entry ready for
+TimeEntriesService.Create. Invalid rows are reported with their line
+number and skipped:
+
+	entries, err := csvio.NewReader(f, names).ReadAll()
+	if errs, ok := err.(csvio.Errors); ok {
+		for _, e := range errs {
+			fmt.Println(e)
+		}
+	} else if err != nil {
+		return err
+	}
+*/
+package csvio
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+	"github.com/gedex/go-toggl/toggl/rates"
+)
+
+// Columns of Toggl's detailed report export, in order.
+const (
+	ColumnUser        = "User"
+	ColumnEmail       = "Email"
+	ColumnClient      = "Client"
+	ColumnProject     = "Project"
+	ColumnTask        = "Task"
+	ColumnDescription = "Description"
+	ColumnBillable    = "Billable"
+	ColumnStartDate   = "Start date"
+	ColumnStartTime   = "Start time"
+	ColumnEndDate     = "End date"
+	ColumnEndTime     = "End time"
+	ColumnDuration    = "Duration"
+	ColumnTags        = "Tags"
+	ColumnAmount      = "Amount"
+)
+
+// Columns lists the columns written by Writer.
+var Columns = []string{
+	ColumnUser, ColumnEmail, ColumnClient, ColumnProject, ColumnTask,
+	ColumnDescription, ColumnBillable, ColumnStartDate, ColumnStartTime,
+	ColumnEndDate, ColumnEndTime, ColumnDuration, ColumnTags, ColumnAmount,
+}
+
+// Layouts of the date and time columns.
+const (
+	DateLayout = "2006-01-02"
+	TimeLayout = "15:04:05"
+)
+
+// Writer writes time entries as CSV records.
+type Writer struct {
+	// Names resolves the names written for IDs. Names are left empty
+	// when it is nil.
+	Names *Names
+
+	// Rates resolves the amount of each time entry. The amount is left
+	// empty when it is nil.
+	Rates *rates.Resolver
+
+	// Currency is shown in the amount column header, as in "Amount (USD)".
+	// The header is "Amount" when it is empty.
+	Currency string
+
+	// Location dates and times are written in. Defaults to time.Local.
+	Location *time.Location
+
+	// Time running entries are measured up to. Defaults to time.Now().
+	Now time.Time
+
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+// NewWriter returns a Writer that writes to w, resolving names with n.
+func NewWriter(w io.Writer, n *Names) *Writer {
+	return &Writer{Names: n, w: csv.NewWriter(w)}
+}
+
+// Write writes te as a CSV record, preceded by the header record if it is
+// the first one. Records are buffered until Flush is called.
+func (w *Writer) Write(te *toggl.TimeEntry) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	loc := w.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	now := w.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	var startDate, startTime, endDate, endTime string
+	if te.Start != nil {
+		start := te.Start.In(loc)
+		startDate, startTime = start.Format(DateLayout), start.Format(TimeLayout)
+
+		if !te.IsRunning() {
+			stop := start.Add(te.Elapsed(now))
+			if te.Stop != nil {
+				stop = te.Stop.In(loc)
+			}
+			endDate, endTime = stop.Format(DateLayout), stop.Format(TimeLayout)
+		}
+	}
+
+	var amount string
+	if w.Rates != nil {
+		a := w.Rates.Amount(te, now)
+		amount = strconv.FormatFloat(a.Value, 'f', rates.MinorUnits(a.Currency), 64)
+	}
+
+	u := w.Names.User(te.UserID)
+	return w.w.Write([]string{
+		u.Fullname,
+		u.Email,
+		w.Names.ClientName(te.ProjectID),
+		w.Names.ProjectName(te.ProjectID),
+		w.Names.TaskName(te.TaskID),
+		te.Description,
+		formatBillable(te.Billable),
+		startDate,
+		startTime,
+		endDate,
+		endTime,
+		FormatDuration(te.Elapsed(now)),
+		strings.Join(te.Tags, ", "),
+		amount,
+	})
+}
+
+// WriteAll writes entries followed by a Flush. The header record is
+// written even if there are no entries.
+func (w *Writer) WriteAll(entries []toggl.TimeEntry) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	for i := range entries {
+		if err := w.Write(&entries[i]); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Flush writes any buffered records to the underlying io.Writer.
+func (w *Writer) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *Writer) writeHeader() error {
+	if w.wroteHeader {
+		return nil
+	}
+	w.wroteHeader = true
+
+	header := append([]string(nil), Columns...)
+	if w.Currency != "" {
+		header[len(header)-1] = fmt.Sprintf("%s (%s)", ColumnAmount, w.Currency)
+	}
+	return w.w.Write(header)
+}
+
+func formatBillable(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
+
+// FormatDuration formats d as hours, minutes and seconds, e.g. "25:03:07".
+func FormatDuration(d time.Duration) string {
+	s := int64(d / time.Second)
+	sign := ""
+	if s < 0 {
+		sign, s = "-", -s
+	}
+	return fmt.Sprintf("%s%02d:%02d:%02d", sign, s/3600, s/60%60, s%60)
+}
+
+// ParseDuration parses a duration formatted by FormatDuration. Hours and
+// minutes without seconds ("1:30") and Go durations ("1h30m") are accepted
+// as well.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, ":") {
+		return time.ParseDuration(s)
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second}[:len(parts)] {
+		n, err := strconv.ParseUint(parts[i], 10, 32)
+		if err != nil || (i > 0 && n > 59) {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		d += time.Duration(n) * unit
+	}
+	return d, nil
+}
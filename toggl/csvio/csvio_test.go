@@ -0,0 +1,219 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csvio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+	"github.com/gedex/go-toggl/toggl/rates"
+)
+
+var names = NewNames(
+	[]toggl.Project{
+		{ID: 100, WorkspaceID: 1, ClientID: 10, Name: "Website"},
+		{ID: 101, WorkspaceID: 1, ClientID: 11, Name: "Website"},
+		{ID: 102, WorkspaceID: 1, Name: "Internal"},
+	},
+	[]toggl.WorkspaceClient{{ID: 10, Name: "Acme"}, {ID: 11, Name: "Globex"}},
+	[]toggl.Task{{ID: 1000, ProjectID: 100, Name: "QA"}},
+	[]toggl.User{{ID: 7, Fullname: "John Doe", Email: "john@example.com"}},
+)
+
+func date(hour, min int) *time.Time {
+	t := time.Date(2013, time.July, 1, hour, min, 0, 0, time.UTC)
+	return &t
+}
+
+func TestWriter(t *testing.T) {
+	entries := []toggl.TimeEntry{
+		{UserID: 7, ProjectID: 100, TaskID: 1000, Description: "Test, fix", Billable: true,
+			Start: date(9, 0), Stop: date(10, 30), Duration: 5400, Tags: []string{"a", "b"}},
+		{UserID: 7, ProjectID: 102, Start: date(11, 0), Duration: int(-date(11, 0).Unix())},
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, names)
+	w.Rates = rates.NewResolver(nil, []toggl.WorkspaceClient{{ID: 10, HourlyRate: 100, Currency: "EUR"}}, []toggl.Project{names.Projects[100], names.Projects[102]}, nil)
+	w.Currency = "EUR"
+	w.Location = time.UTC
+	w.Now = *date(11, 15)
+	if err := w.WriteAll(entries); err != nil {
+		t.Fatalf("WriteAll returned error: %v", err)
+	}
+
+	want := "User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount (EUR)\n" +
+		`John Doe,john@example.com,Acme,Website,QA,"Test, fix",Yes,2013-07-01,09:00:00,2013-07-01,10:30:00,01:30:00,"a, b",150.00` + "\n" +
+		"John Doe,john@example.com,,Internal,,,No,2013-07-01,11:00:00,,,00:15:00,,0.00\n"
+	if buf.String() != want {
+		t.Errorf("WriteAll wrote\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestWriter_noCurrency(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, nil)
+	if err := w.WriteAll(nil); err != nil {
+		t.Fatalf("WriteAll returned error: %v", err)
+	}
+
+	want := "User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount\n"
+	if buf.String() != want {
+		t.Errorf("WriteAll wrote %q, want %q", buf.String(), want)
+	}
+}
+
+func TestReader_roundTrip(t *testing.T) {
+	entries := []toggl.TimeEntry{
+		{ProjectID: 101, Description: "Design", Billable: true, Start: date(9, 0), Stop: date(10, 30), Duration: 5400, Tags: []string{"a", "b"}},
+		{ProjectID: 100, TaskID: 1000, Start: date(11, 0), Stop: date(11, 1), Duration: 60},
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, names)
+	w.Location = time.UTC
+	if err := w.WriteAll(entries); err != nil {
+		t.Fatalf("WriteAll returned error: %v", err)
+	}
+
+	r := NewReader(&buf, names)
+	r.Location = time.UTC
+	got, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll returned error: %v", err)
+	}
+
+	for i := range entries {
+		entries[i].WorkspaceID = 1
+		entries[i].CreatedWith = toggl.UserAgent
+	}
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("ReadAll returned %+v, want %+v", got, entries)
+	}
+}
+
+func TestReader_rowErrors(t *testing.T) {
+	input := "\ufeffProject,Client,Task,Start date,Start time,End date,End time,Billable\n" +
+		"Internal,,,2013-07-01,09:00,2013-07-01,09:30,yes\n" +
+		"Website,,,2013-07-01,09:00,2013-07-01,09:30,\n" +
+		"Unknown,,,2013-07-01,09:00,2013-07-01,09:30,\n" +
+		"Website,Acme,QA,2013-07-01,09:00,2013-07-01,08:30,\n" +
+		"Website,Acme,Nope,2013-07-01,09:00,2013-07-01,09:30,\n" +
+		",,,2013-13-01,09:00,2013-07-01,09:30,\n" +
+		",,,2013-07-01,09:00,2013-07-01,09:30,maybe\n" +
+		"\"Website\nWebsite\",,,2013-07-01,09:00,2013-07-01,09:30,\n" +
+		"Internal,,,2013-07-01,09:00,2013-07-01,09:30,no\n"
+
+	r := NewReader(strings.NewReader(input), names)
+	r.Location = time.UTC
+	r.WorkspaceID = 2
+	entries, err := r.ReadAll()
+
+	errs, ok := err.(Errors)
+	if !ok {
+		t.Fatalf("ReadAll returned error %v, want Errors", err)
+	}
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Error())
+	}
+	want := []string{
+		`line 3: Project: ambiguous project "Website"`,
+		`line 4: Project: unknown project "Unknown"`,
+		`line 5: Duration: Invalid duration -30m0s`,
+		`line 6: Task: unknown task "Nope"`,
+		`line 7: Start date: invalid date and time "2013-13-01 09:00"`,
+		`line 8: Billable: invalid billable "maybe"`,
+		`line 9: Project: unknown project "Website\nWebsite"`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadAll returned errors %q, want %q", got, want)
+	}
+
+	if len(entries) != 2 || entries[0].ProjectID != 102 || !entries[0].Billable || entries[1].Billable {
+		t.Errorf("ReadAll returned entries %+v, want the 2 valid rows", entries)
+	}
+	if entries[0].WorkspaceID != 1 || entries[0].Duration != 1800 {
+		t.Errorf("ReadAll returned entry %+v, want workspace 1 lasting 1800s", entries[0])
+	}
+}
+
+func TestReader_missingColumn(t *testing.T) {
+	r := NewReader(strings.NewReader("Start date,Start time,End date\n"), nil)
+	if _, err := r.Read(); err == nil || err.Error() != `missing column "End time"` {
+		t.Errorf("Read returned error %v, want missing column", err)
+	}
+
+	r = NewReader(strings.NewReader("Start date,Start time,Duration\n"), nil)
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("Read returned error %v, want %v", err, io.EOF)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"25:03:07", 25*time.Hour + 3*time.Minute + 7*time.Second, true},
+		{"1:30", 90 * time.Minute, true},
+		{"1h30m", 90 * time.Minute, true},
+		{"1:60", 0, false},
+		{"1:2:3:4", 0, false},
+		{"a:00", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+	if got := FormatDuration(-90 * time.Second); got != "-00:01:30" {
+		t.Errorf("FormatDuration(-90s) = %q, want %q", got, "-00:01:30")
+	}
+}
+
+func TestLoadNames(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	responses := map[string]string{
+		"/workspaces/1/projects": `[{"id": 100, "cid": 10, "name": "Website"}]`,
+		"/workspaces/1/clients":  `[{"id": 10, "name": "Acme"}]`,
+		"/workspaces/1/tasks":    `[{"id": 1000, "pid": 100, "name": "QA"}]`,
+		"/workspaces/1/users":    `[{"id": 7, "fullname": "John Doe"}]`,
+	}
+	for path, body := range responses {
+		body := body
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		})
+	}
+
+	u, _ := url.Parse(server.URL)
+	n, err := LoadNames(context.Background(), toggl.NewClient("", toggl.WithBaseURL(u)), 1)
+	if err != nil {
+		t.Fatalf("LoadNames returned error: %v", err)
+	}
+
+	got := []string{n.ClientName(100), n.ProjectName(100), n.TaskName(1000), n.User(7).Fullname}
+	if want := []string{"Acme", "Website", "QA", "John Doe"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadNames resolved %q, want %q", got, want)
+	}
+}
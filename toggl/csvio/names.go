@@ -0,0 +1,154 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csvio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+// Names resolves the IDs referenced by time entries to names and back. A
+// nil *Names resolves nothing.
+type Names struct {
+	Projects map[int]toggl.Project
+	Clients  map[int]toggl.WorkspaceClient
+	Tasks    map[int]toggl.Task
+	Users    map[int]toggl.User
+}
+
+// NewNames returns Names for the given objects.
+func NewNames(projects []toggl.Project, clients []toggl.WorkspaceClient, tasks []toggl.Task, users []toggl.User) *Names {
+	n := &Names{
+		Projects: make(map[int]toggl.Project, len(projects)),
+		Clients:  make(map[int]toggl.WorkspaceClient, len(clients)),
+		Tasks:    make(map[int]toggl.Task, len(tasks)),
+		Users:    make(map[int]toggl.User, len(users)),
+	}
+	for _, p := range projects {
+		n.Projects[p.ID] = p
+	}
+	for _, c := range clients {
+		n.Clients[c.ID] = c
+	}
+	for _, t := range tasks {
+		n.Tasks[t.ID] = t
+	}
+	for _, u := range users {
+		n.Users[u.ID] = u
+	}
+	return n
+}
+
+// LoadNames fetches the projects, clients, tasks and users of the
+// workspace with the given ID.
+func LoadNames(ctx context.Context, c *toggl.Client, wid int) (*Names, error) {
+	projects, err := c.Workspaces.ListProjectsContext(ctx, wid, "")
+	if err != nil {
+		return nil, err
+	}
+	clients, err := c.Workspaces.ListClientsContext(ctx, wid)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := c.Workspaces.ListTasksContext(ctx, wid, "")
+	if err != nil {
+		return nil, err
+	}
+	users, err := c.Workspaces.ListUsersContext(ctx, wid)
+	if err != nil {
+		return nil, err
+	}
+	return NewNames(projects, clients, tasks, users), nil
+}
+
+// ProjectName returns the name of the project with the given ID, or ""
+// if it is unknown.
+func (n *Names) ProjectName(id int) string {
+	if n == nil {
+		return ""
+	}
+	return n.Projects[id].Name
+}
+
+// ClientName returns the name of the client of the project with the given
+// ID, or "" if it is unknown.
+func (n *Names) ClientName(projectID int) string {
+	if n == nil {
+		return ""
+	}
+	return n.Clients[n.Projects[projectID].ClientID].Name
+}
+
+// TaskName returns the name of the task with the given ID, or "" if it is
+// unknown.
+func (n *Names) TaskName(id int) string {
+	if n == nil {
+		return ""
+	}
+	return n.Tasks[id].Name
+}
+
+// User returns the user with the given ID, or the zero User if it is
+// unknown.
+func (n *Names) User(id int) toggl.User {
+	if n == nil {
+		return toggl.User{}
+	}
+	return n.Users[id]
+}
+
+// ProjectID returns the ID of the project with the given name. Names are
+// compared case-insensitively. If client is not empty, only projects of
+// the client with that name match.
+func (n *Names) ProjectID(client, project string) (int, error) {
+	var ids []int
+	if n != nil {
+		for _, p := range n.Projects {
+			if !strings.EqualFold(p.Name, project) {
+				continue
+			}
+			if client != "" && !strings.EqualFold(n.Clients[p.ClientID].Name, client) {
+				continue
+			}
+			ids = append(ids, p.ID)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		if client != "" {
+			return 0, fmt.Errorf("unknown project %q of client %q", project, client)
+		}
+		return 0, fmt.Errorf("unknown project %q", project)
+	case 1:
+		return ids[0], nil
+	}
+	return 0, fmt.Errorf("ambiguous project %q", project)
+}
+
+// TaskID returns the ID of the task with the given name in the project
+// with the given ID. Names are compared case-insensitively.
+func (n *Names) TaskID(projectID int, task string) (int, error) {
+	var ids []int
+	if n != nil {
+		for _, t := range n.Tasks {
+			if t.ProjectID == projectID && strings.EqualFold(t.Name, task) {
+				ids = append(ids, t.ID)
+			}
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return 0, fmt.Errorf("unknown task %q", task)
+	case 1:
+		return ids[0], nil
+	}
+	return 0, fmt.Errorf("ambiguous task %q", task)
+}
@@ -0,0 +1,275 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csvio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+// RowError reports an invalid CSV record.
+type RowError struct {
+	Line   int    // line the record starts on
+	Column string // column of the invalid field, if any
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d: %s: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Errors reports the invalid records skipped by Reader.ReadAll.
+type Errors []*RowError
+
+func (e Errors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+// Reader reads time entries from CSV records in the columns written by
+// Writer. The first record is the header; columns are matched by name,
+// case-insensitively, and unknown ones are ignored. Start date, Start time
+// and either Duration or End date and End time are required.
+type Reader struct {
+	// Names resolves project and task names. Records naming a project
+	// are invalid when it is nil.
+	Names *Names
+
+	// Location dates and times without a zone are read in. Defaults to
+	// time.Local.
+	Location *time.Location
+
+	// WorkspaceID of entries without a project.
+	WorkspaceID int
+
+	// CreatedWith is set on every entry. Defaults to toggl.UserAgent.
+	CreatedWith string
+
+	r       *csv.Reader
+	columns map[string]int
+}
+
+// NewReader returns a Reader that reads from r, resolving names with n.
+func NewReader(r io.Reader, n *Names) *Reader {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	return &Reader{Names: n, r: cr}
+}
+
+// Read reads the next time entry. It returns a *RowError for an invalid
+// record, after which reading can continue, and io.EOF at the end of the
+// input.
+func (r *Reader) Read() (*toggl.TimeEntry, error) {
+	if r.columns == nil {
+		if err := r.readHeader(); err != nil {
+			return nil, err
+		}
+	}
+
+	record, err := r.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	line, _ := r.r.FieldPos(0)
+
+	te, column, err := r.parse(record)
+	if err != nil {
+		return nil, &RowError{Line: line, Column: column, Err: err}
+	}
+	return te, nil
+}
+
+// ReadAll reads the remaining time entries. Invalid records are skipped
+// and reported together as Errors once the input is read.
+func (r *Reader) ReadAll() ([]toggl.TimeEntry, error) {
+	var entries []toggl.TimeEntry
+	var errs Errors
+	for {
+		te, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		var rerr *RowError
+		if errors.As(err, &rerr) {
+			errs = append(errs, rerr)
+			continue
+		}
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, *te)
+	}
+
+	if errs != nil {
+		return entries, errs
+	}
+	return entries, nil
+}
+
+func (r *Reader) readHeader() error {
+	header, err := r.r.Read()
+	if err == io.EOF {
+		return errors.New("missing header")
+	}
+	if err != nil {
+		return err
+	}
+
+	r.columns = make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		// "Amount (USD)" is matched as "amount".
+		if j := strings.Index(name, "("); j > 0 {
+			name = name[:j]
+		}
+		r.columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	required := []string{ColumnStartDate, ColumnStartTime}
+	if !r.has(ColumnDuration) {
+		required = append(required, ColumnEndDate, ColumnEndTime)
+	}
+	for _, c := range required {
+		if !r.has(c) {
+			return fmt.Errorf("missing column %q", c)
+		}
+	}
+	return nil
+}
+
+func (r *Reader) has(column string) bool {
+	_, ok := r.columns[strings.ToLower(column)]
+	return ok
+}
+
+// field returns the trimmed value of column in record, or "" if there is
+// no such column.
+func (r *Reader) field(record []string, column string) string {
+	i, ok := r.columns[strings.ToLower(column)]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// parse returns the time entry of record, or the column of the invalid
+// field and the error.
+func (r *Reader) parse(record []string) (*toggl.TimeEntry, string, error) {
+	loc := r.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	start, err := parseTime(r.field(record, ColumnStartDate), r.field(record, ColumnStartTime), loc)
+	if err != nil {
+		return nil, ColumnStartDate, err
+	}
+
+	var d time.Duration
+	if s := r.field(record, ColumnDuration); s != "" {
+		if d, err = ParseDuration(s); err != nil {
+			return nil, ColumnDuration, err
+		}
+	} else {
+		stop, err := parseTime(r.field(record, ColumnEndDate), r.field(record, ColumnEndTime), loc)
+		if err != nil {
+			return nil, ColumnEndDate, err
+		}
+		d = stop.Sub(start)
+	}
+
+	te, err := toggl.NewTimeEntry(start, d)
+	if err != nil {
+		return nil, ColumnDuration, err
+	}
+	te.WorkspaceID = r.WorkspaceID
+	te.Description = r.field(record, ColumnDescription)
+	te.CreatedWith = r.CreatedWith
+	if te.CreatedWith == "" {
+		te.CreatedWith = toggl.UserAgent
+	}
+
+	if s := r.field(record, ColumnBillable); s != "" {
+		if te.Billable, err = parseBillable(s); err != nil {
+			return nil, ColumnBillable, err
+		}
+	}
+
+	for _, tag := range strings.Split(r.field(record, ColumnTags), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			te.Tags = append(te.Tags, tag)
+		}
+	}
+
+	if project := r.field(record, ColumnProject); project != "" {
+		if te.ProjectID, err = r.Names.ProjectID(r.field(record, ColumnClient), project); err != nil {
+			return nil, ColumnProject, err
+		}
+		if wid := r.Names.Projects[te.ProjectID].WorkspaceID; wid != 0 {
+			te.WorkspaceID = wid
+		}
+	}
+
+	if task := r.field(record, ColumnTask); task != "" {
+		if te.ProjectID == 0 {
+			return nil, ColumnTask, errors.New("task without project")
+		}
+		if te.TaskID, err = r.Names.TaskID(te.ProjectID, task); err != nil {
+			return nil, ColumnTask, err
+		}
+	}
+
+	return te, "", nil
+}
+
+func parseTime(date, clock string, loc *time.Location) (time.Time, error) {
+	if date == "" || clock == "" {
+		return time.Time{}, errors.New("missing date or time")
+	}
+	t, err := time.ParseInLocation(DateLayout+" "+TimeLayout, date+" "+clock, loc)
+	if err != nil {
+		// Seconds are optional.
+		t, err = time.ParseInLocation(DateLayout+" 15:04", date+" "+clock, loc)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date and time %q", date+" "+clock)
+	}
+	return t, nil
+}
+
+func parseBillable(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "yes":
+		return true, nil
+	case "no":
+		return false, nil
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("invalid billable %q", s)
+	}
+	return b, nil
+}
@@ -0,0 +1,224 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package ical converts Toggl time entries to and from iCalendar (RFC 5545),
+so that tracked time can be shown in calendar apps.
+
+Write writes a VCALENDAR with one VEVENT per time entry. Event UIDs are
+derived from the entry IDs, so calendars importing an updated file update
+their events instead of duplicating them:
+
+	names, err := csvio.LoadNames(ctx, c, wid)
+	if err != nil {
+		return err
+	}
+	err = ical.Write(f, entries, &ical.Options{Names: names})
+
+Read does the reverse, turning the VEVENTs of a calendar into new time
+entries ready for TimeEntriesService.Create.
+*/
+package ical
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+// Namer resolves project and task IDs to names. *csvio.Names implements
+// it.
+type Namer interface {
+	ProjectName(id int) string
+	TaskName(id int) string
+}
+
+// Options configures Write.
+type Options struct {
+	// Names adds project and task names to the events.
+	Names Namer
+
+	// Domain the UIDs are qualified with. Defaults to "toggl.com".
+	Domain string
+
+	// Time running entries end at. Defaults to time.Now().
+	Now time.Time
+}
+
+// Extension properties carrying the Toggl fields without an iCalendar
+// equivalent, read back by Read.
+const (
+	PropertyProjectID = "X-TOGGL-PROJECT-ID"
+	PropertyTaskID    = "X-TOGGL-TASK-ID"
+	PropertyBillable  = "X-TOGGL-BILLABLE"
+)
+
+const (
+	prodID = "-//go-toggl//go-toggl " + toggl.LibraryVersion + "//EN"
+
+	// Layout of UTC DATE-TIME values.
+	utcLayout = "20060102T150405Z"
+
+	// Lines longer than this many octets are folded.
+	maxLineLength = 75
+)
+
+// Write writes entries to w as an iCalendar object. opts may be nil.
+func Write(w io.Writer, entries []toggl.TimeEntry, opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	domain := opts.Domain
+	if domain == "" {
+		domain = "toggl.com"
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	cw := &contentWriter{w: bufio.NewWriter(w)}
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", prodID)
+	cw.line("CALSCALE", "GREGORIAN")
+
+	for i := range entries {
+		te := &entries[i]
+		if te.Start == nil {
+			continue
+		}
+		start := te.Start.UTC()
+		stop := start.Add(te.Elapsed(now))
+		if te.Stop != nil && !te.IsRunning() {
+			stop = te.Stop.UTC()
+		}
+		stamp := now
+		if te.At != nil {
+			stamp = *te.At
+		}
+
+		cw.line("BEGIN", "VEVENT")
+		cw.line("UID", uid(te, domain))
+		cw.line("DTSTAMP", stamp.UTC().Format(utcLayout))
+		cw.line("DTSTART", start.Format(utcLayout))
+		cw.line("DTEND", stop.Format(utcLayout))
+		cw.line("SUMMARY", escape(summary(te, opts.Names)))
+		if d := description(te, opts.Names); d != "" && te.Description != "" {
+			cw.line("DESCRIPTION", escape(d))
+		}
+		if len(te.Tags) > 0 {
+			tags := make([]string, len(te.Tags))
+			for i, tag := range te.Tags {
+				tags[i] = escape(tag)
+			}
+			cw.line("CATEGORIES", strings.Join(tags, ","))
+		}
+		cw.line("TRANSP", "TRANSPARENT")
+		if te.ProjectID != 0 {
+			cw.line(PropertyProjectID, strconv.Itoa(te.ProjectID))
+		}
+		if te.TaskID != 0 {
+			cw.line(PropertyTaskID, strconv.Itoa(te.TaskID))
+		}
+		if te.Billable {
+			cw.line(PropertyBillable, "TRUE")
+		}
+		cw.line("END", "VEVENT")
+	}
+
+	cw.line("END", "VCALENDAR")
+	if cw.err != nil {
+		return cw.err
+	}
+	return cw.w.Flush()
+}
+
+// uid returns a UID that stays the same across exports of te.
+func uid(te *toggl.TimeEntry, domain string) string {
+	switch {
+	case te.ID != 0:
+		return fmt.Sprintf("time-entry-%d@%s", te.ID, domain)
+	case te.GUID != "":
+		return fmt.Sprintf("time-entry-%s@%s", te.GUID, domain)
+	}
+	// Entries not created yet have neither, so their start and
+	// description identify them.
+	h := sha1.Sum([]byte(te.Start.UTC().Format(utcLayout) + te.Description))
+	return fmt.Sprintf("time-entry-%x@%s", h[:10], domain)
+}
+
+// summary returns the description of te, falling back to its project and
+// task names.
+func summary(te *toggl.TimeEntry, names Namer) string {
+	if te.Description != "" {
+		return te.Description
+	}
+	if d := description(te, names); d != "" {
+		return d
+	}
+	return "(no description)"
+}
+
+// description returns the project and task names of te.
+func description(te *toggl.TimeEntry, names Namer) string {
+	if names == nil {
+		return ""
+	}
+	d := names.ProjectName(te.ProjectID)
+	if task := names.TaskName(te.TaskID); task != "" {
+		if d != "" {
+			d += " / "
+		}
+		d += task
+	}
+	return d
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// contentWriter writes content lines, folding them as needed. The first
+// error is kept in err and stops further writes.
+type contentWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (cw *contentWriter) line(name, value string) {
+	if cw.err != nil {
+		return
+	}
+
+	s := name + ":" + value
+	for len(s) > maxLineLength {
+		// Fold without splitting a UTF-8 sequence. The space starting
+		// continuation lines counts toward their length.
+		n := maxLineLength
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+		if _, cw.err = cw.w.WriteString(s[:n] + "\r\n"); cw.err != nil {
+			return
+		}
+		s = " " + s[n:]
+	}
+	_, cw.err = cw.w.WriteString(s + "\r\n")
+}
@@ -0,0 +1,233 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ical
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+	"github.com/gedex/go-toggl/toggl/csvio"
+)
+
+var _ Namer = (*csvio.Names)(nil)
+
+var names = csvio.NewNames(
+	[]toggl.Project{{ID: 100, Name: "Website"}},
+	nil,
+	[]toggl.Task{{ID: 1000, ProjectID: 100, Name: "QA"}},
+	nil,
+)
+
+func date(hour, min int) *time.Time {
+	t := time.Date(2013, time.July, 1, hour, min, 0, 0, time.UTC)
+	return &t
+}
+
+func TestWrite(t *testing.T) {
+	entries := []toggl.TimeEntry{
+		{ID: 1, ProjectID: 100, TaskID: 1000, Description: "Fix; test, deploy\nagain", Billable: true,
+			Start: date(9, 0), Stop: date(10, 30), Duration: 5400, Tags: []string{"a,b", "c"}, At: date(10, 31)},
+		{GUID: "abc", ProjectID: 100, Start: date(11, 0), Duration: int(-date(11, 0).Unix())},
+		{Description: strings.Repeat("é", 40), Start: date(12, 0), Duration: 60},
+		{Description: "no start", Duration: 60},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, entries, &Options{Names: names, Now: *date(11, 15)}); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//go-toggl//go-toggl " + toggl.LibraryVersion + "//EN",
+		"CALSCALE:GREGORIAN",
+		"BEGIN:VEVENT",
+		"UID:time-entry-1@toggl.com",
+		"DTSTAMP:20130701T103100Z",
+		"DTSTART:20130701T090000Z",
+		"DTEND:20130701T103000Z",
+		`SUMMARY:Fix\; test\, deploy\nagain`,
+		"DESCRIPTION:Website / QA",
+		`CATEGORIES:a\,b,c`,
+		"TRANSP:TRANSPARENT",
+		"X-TOGGL-PROJECT-ID:100",
+		"X-TOGGL-TASK-ID:1000",
+		"X-TOGGL-BILLABLE:TRUE",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:time-entry-abc@toggl.com",
+		"DTSTAMP:20130701T111500Z",
+		"DTSTART:20130701T110000Z",
+		"DTEND:20130701T111500Z",
+		"SUMMARY:Website",
+		"TRANSP:TRANSPARENT",
+		"X-TOGGL-PROJECT-ID:100",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:" + uid(&entries[2], "toggl.com"),
+		"DTSTAMP:20130701T111500Z",
+		"DTSTART:20130701T120000Z",
+		"DTEND:20130701T120100Z",
+		"SUMMARY:" + strings.Repeat("é", 33),
+		" " + strings.Repeat("é", 7),
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if buf.String() != want {
+		t.Errorf("Write wrote\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestWrite_stableUIDs(t *testing.T) {
+	te := toggl.TimeEntry{Description: "new", Start: date(9, 0), Duration: 60}
+	if a, b := uid(&te, "example.com"), uid(&te, "example.com"); a != b {
+		t.Errorf("uid returned %q and %q for the same entry", a, b)
+	}
+	te.Description = "other"
+	if a, b := uid(&te, "example.com"), uid(&toggl.TimeEntry{Start: date(9, 0)}, "example.com"); a == b {
+		t.Errorf("uid returned %q for different entries", a)
+	}
+}
+
+func TestRead_roundTrip(t *testing.T) {
+	entries := []toggl.TimeEntry{
+		{ProjectID: 100, TaskID: 1000, Description: "Fix; test, deploy\nagain " + strings.Repeat("é", 40), Billable: true,
+			Start: date(9, 0), Stop: date(10, 30), Duration: 5400, Tags: []string{"a,b", "c"}},
+		{ProjectID: 100, Start: date(11, 0), Stop: date(11, 15), Duration: 900},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, entries, &Options{Names: names}); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	got, err := Read(&buf, &ReadOptions{WorkspaceID: 1})
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+
+	for i := range entries {
+		entries[i].WorkspaceID = 1
+		entries[i].CreatedWith = toggl.UserAgent
+	}
+	// The second entry has no description; its summary is the project name.
+	entries[1].Description = "Website"
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("Read returned %+v, want %+v", got, entries)
+	}
+}
+
+func TestRead(t *testing.T) {
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Berlin",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"DTSTART;TZID=\"Europe/Berlin\":20130701T090000",
+		"DURATION:PT1H3",
+		" 0M",
+		"SUMMARY:Meeting",
+		"CATEGORIES:x",
+		"CATEGORIES:y",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT15M",
+		"DESCRIPTION:Reminder",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20130702",
+		"SUMMARY:Workshop",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20130703T090000",
+		"DTEND:20130703T100000",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20130704T090000",
+		"DTEND:20130704T093000",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\n")
+
+	got, err := Read(strings.NewReader(input), &ReadOptions{Location: time.UTC, CreatedWith: "test"})
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	want := []struct {
+		start       time.Time
+		duration    int
+		description string
+		tags        []string
+	}{
+		{time.Date(2013, time.July, 1, 9, 0, 0, 0, berlin), 5400, "Meeting", []string{"x", "y"}},
+		{time.Date(2013, time.July, 2, 0, 0, 0, 0, time.UTC), 86400, "Workshop", nil},
+		{time.Date(2013, time.July, 4, 9, 0, 0, 0, time.UTC), 1800, "", nil},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Read returned %d entries, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		te := got[i]
+		if !te.Start.Equal(w.start) || te.Duration != w.duration || te.Description != w.description ||
+			!reflect.DeepEqual(te.Tags, w.tags) || te.CreatedWith != "test" {
+			t.Errorf("Read returned entry %d %+v, want %+v", i, te, w)
+		}
+	}
+}
+
+func TestRead_errors(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"BEGIN:VEVENT\nSUMMARY:x\nEND:VEVENT", "line 1: missing DTSTART"},
+		{"BEGIN:VEVENT\nDTSTART:20130701T090000Z\nEND:VEVENT", "line 1: missing DTEND or DURATION"},
+		{"X\nBEGIN:VEVENT", "line 1: invalid content line \"X\""},
+		{"BEGIN:VEVENT\nDTSTART:20130701T090000Z\nDTEND:20130701T080000Z\nEND:VEVENT", "line 1: Invalid duration -1h0m0s"},
+		{"\nBEGIN:VEVENT\nDTSTART;TZID=Nowhere:20130701T090000\nDURATION:PT1H\nEND:VEVENT", "line 2: DTSTART: unknown time zone \"Nowhere\""},
+		{"BEGIN:VEVENT\nDTSTART:20130701T090000Z\nDURATION:PT1X\nEND:VEVENT", "line 1: DURATION: invalid duration \"PT1X\""},
+		{"BEGIN:VEVENT\nDTSTART:20130701T090000Z", "line 1: unterminated VEVENT"},
+	}
+
+	for _, tt := range tests {
+		_, err := Read(strings.NewReader(tt.input), nil)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Read(%q) returned error %v, want %v", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"PT1H30M", 90 * time.Minute},
+		{"P1DT2H", 26 * time.Hour},
+		{"P1W", 7 * 24 * time.Hour},
+		{"+PT15S", 15 * time.Second},
+	}
+	for _, tt := range tests {
+		if got, err := parseDuration(tt.in); err != nil || got != tt.want {
+			t.Errorf("parseDuration(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "P", "PT", "-PT1H", "P1H", "PT1", "1H"} {
+		if _, err := parseDuration(in); err == nil {
+			t.Errorf("parseDuration(%q) returned no error", in)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+// ReadOptions configures Read.
+type ReadOptions struct {
+	// WorkspaceID set on every entry.
+	WorkspaceID int
+
+	// Location of times without a time zone. Defaults to time.Local.
+	Location *time.Location
+
+	// CreatedWith is set on every entry. Defaults to toggl.UserAgent.
+	CreatedWith string
+}
+
+// property represents a content line.
+type property struct {
+	params map[string]string
+	value  string
+}
+
+// event holds the properties of a VEVENT, by name.
+type event struct {
+	line  int // line of BEGIN:VEVENT
+	props map[string][]property
+}
+
+func (e *event) get(name string) (property, bool) {
+	p := e.props[name]
+	if len(p) == 0 {
+		return property{}, false
+	}
+	return p[0], true
+}
+
+// Read reads the VEVENTs of the iCalendar objects in r as new time
+// entries, i.e. without IDs. Cancelled events are skipped. opts may be
+// nil.
+func Read(r io.Reader, opts *ReadOptions) ([]toggl.TimeEntry, error) {
+	if opts == nil {
+		opts = &ReadOptions{}
+	}
+	events, err := readEvents(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []toggl.TimeEntry
+	for _, e := range events {
+		if p, _ := e.get("STATUS"); strings.EqualFold(p.value, "CANCELLED") {
+			continue
+		}
+		te, err := opts.entry(e)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", e.line, err)
+		}
+		entries = append(entries, *te)
+	}
+	return entries, nil
+}
+
+// readEvents unfolds and parses the content lines of r, returning the
+// VEVENTs. Properties of components nested in a VEVENT, like VALARM, are
+// ignored.
+func readEvents(r io.Reader) ([]*event, error) {
+	var (
+		events  []*event
+		current *event
+		depth   int // of components nested in current
+	)
+
+	handle := func(n int, line string) error {
+		if line == "" {
+			return nil
+		}
+		name, p, err := parseLine(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", n, err)
+		}
+
+		switch {
+		case name == "BEGIN" && current == nil:
+			if strings.EqualFold(p.value, "VEVENT") {
+				current = &event{line: n, props: make(map[string][]property)}
+			}
+		case name == "BEGIN":
+			depth++
+		case name == "END" && current != nil && depth > 0:
+			depth--
+		case name == "END" && current != nil:
+			events = append(events, current)
+			current = nil
+		case current != nil && depth == 0:
+			current.props[name] = append(current.props[name], p)
+		}
+		return nil
+	}
+
+	s := bufio.NewScanner(r)
+	var line string
+	start, n := 0, 0
+	for s.Scan() {
+		n++
+		text := strings.TrimSuffix(s.Text(), "\r")
+		if strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t") {
+			line += text[1:]
+			continue
+		}
+		if err := handle(start, line); err != nil {
+			return nil, err
+		}
+		line, start = text, n
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if err := handle(start, line); err != nil {
+		return nil, err
+	}
+	if current != nil {
+		return nil, fmt.Errorf("line %d: unterminated VEVENT", current.line)
+	}
+	return events, nil
+}
+
+// parseLine splits a content line into its upper-cased name, parameters
+// and value.
+func parseLine(line string) (string, property, error) {
+	// The value starts at the first colon outside of a quoted parameter
+	// value.
+	quoted, i := false, 0
+	for ; i < len(line); i++ {
+		if c := line[i]; c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			break
+		}
+	}
+	if i == len(line) {
+		return "", property{}, fmt.Errorf("invalid content line %q", line)
+	}
+
+	p := property{value: line[i+1:]}
+	parts := strings.Split(line[:i], ";")
+	for _, param := range parts[1:] {
+		if j := strings.Index(param, "="); j > 0 {
+			if p.params == nil {
+				p.params = make(map[string]string)
+			}
+			p.params[strings.ToUpper(param[:j])] = strings.Trim(param[j+1:], `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), p, nil
+}
+
+// entry returns the time entry of e.
+func (opts *ReadOptions) entry(e *event) (*toggl.TimeEntry, error) {
+	dtstart, ok := e.get("DTSTART")
+	if !ok {
+		return nil, errors.New("missing DTSTART")
+	}
+	start, err := opts.parseTime(dtstart)
+	if err != nil {
+		return nil, fmt.Errorf("DTSTART: %v", err)
+	}
+
+	var d time.Duration
+	if p, ok := e.get("DTEND"); ok {
+		stop, err := opts.parseTime(p)
+		if err != nil {
+			return nil, fmt.Errorf("DTEND: %v", err)
+		}
+		d = stop.Sub(start)
+	} else if p, ok := e.get("DURATION"); ok {
+		if d, err = parseDuration(p.value); err != nil {
+			return nil, fmt.Errorf("DURATION: %v", err)
+		}
+	} else if dtstart.params["VALUE"] == "DATE" {
+		// All day events without an end last one day.
+		d = 24 * time.Hour
+	} else {
+		return nil, errors.New("missing DTEND or DURATION")
+	}
+
+	te, err := toggl.NewTimeEntry(start, d)
+	if err != nil {
+		return nil, err
+	}
+	te.WorkspaceID = opts.WorkspaceID
+	te.CreatedWith = opts.CreatedWith
+	if te.CreatedWith == "" {
+		te.CreatedWith = toggl.UserAgent
+	}
+
+	if p, ok := e.get("SUMMARY"); ok {
+		if s := unescape(p.value); s != "(no description)" {
+			te.Description = s
+		}
+	}
+	for _, p := range e.props["CATEGORIES"] {
+		for _, tag := range splitText(p.value) {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				te.Tags = append(te.Tags, tag)
+			}
+		}
+	}
+
+	if p, ok := e.get(PropertyProjectID); ok {
+		if te.ProjectID, err = strconv.Atoi(p.value); err != nil {
+			return nil, fmt.Errorf("%s: invalid ID %q", PropertyProjectID, p.value)
+		}
+	}
+	if p, ok := e.get(PropertyTaskID); ok {
+		if te.TaskID, err = strconv.Atoi(p.value); err != nil {
+			return nil, fmt.Errorf("%s: invalid ID %q", PropertyTaskID, p.value)
+		}
+	}
+	if p, ok := e.get(PropertyBillable); ok {
+		te.Billable = strings.EqualFold(p.value, "TRUE")
+	}
+
+	return te, nil
+}
+
+// parseTime parses a DATE or DATE-TIME value, in UTC, in the time zone
+// of its TZID parameter or, when floating, in opts.Location.
+func (opts *ReadOptions) parseTime(p property) (time.Time, error) {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	if tzid, ok := p.params["TZID"]; ok {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, fmt.Errorf("unknown time zone %q", tzid)
+		}
+	}
+
+	layout := "20060102T150405"
+	switch {
+	case p.params["VALUE"] == "DATE":
+		layout = "20060102"
+	case strings.HasSuffix(p.value, "Z"):
+		layout, loc = utcLayout, time.UTC
+	}
+
+	t, err := time.ParseInLocation(layout, p.value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", p.value)
+	}
+	return t, nil
+}
+
+// parseDuration parses a DURATION value such as "PT1H30M" or "P1D".
+func parseDuration(s string) (time.Duration, error) {
+	v := strings.TrimPrefix(s, "+")
+	if strings.HasPrefix(v, "-") || !strings.HasPrefix(v, "P") || len(v) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}
+	var d time.Duration
+	n := -1
+	for i := 1; i < len(v); i++ {
+		c := v[i]
+		switch {
+		case c >= '0' && c <= '9':
+			if n < 0 {
+				n = 0
+			}
+			n = n*10 + int(c-'0')
+		case c == 'T' && n < 0:
+			units = map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+		case units[c] != 0 && n >= 0:
+			d += time.Duration(n) * units[c]
+			n = -1
+		default:
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+	}
+	if n >= 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// unescape unescapes a TEXT value.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			if s[i] == 'n' || s[i] == 'N' {
+				b.WriteByte('\n')
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// splitText splits a list of TEXT values at unescaped commas and
+// unescapes them.
+func splitText(s string) []string {
+	var values []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, unescape(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(values, unescape(s[start:]))
+}
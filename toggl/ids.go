@@ -0,0 +1,51 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggl
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxIDsPerRequest is the largest number of IDs sent in a single mass
+// create, update or delete request. Longer ID lists are split into several
+// requests whose results are merged.
+const MaxIDsPerRequest = 100
+
+// validateIDs checks that ids is not empty and holds only valid IDs.
+func validateIDs(ids []int) error {
+	if len(ids) == 0 {
+		return errors.New("ids cannot be empty")
+	}
+	for _, id := range ids {
+		if id <= 0 {
+			return fmt.Errorf("Invalid ID %v", id)
+		}
+	}
+	return nil
+}
+
+// chunkIDs splits ids into lists of at most MaxIDsPerRequest IDs.
+func chunkIDs(ids []int) [][]int {
+	var chunks [][]int
+	for len(ids) > MaxIDsPerRequest {
+		chunks = append(chunks, ids[:MaxIDsPerRequest])
+		ids = ids[MaxIDsPerRequest:]
+	}
+	return append(chunks, ids)
+}
+
+// joinIDs formats ids as the comma separated list expected by the mass
+// update and delete endpoints.
+func joinIDs(ids []int) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = strconv.Itoa(id)
+	}
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,54 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateIDs(t *testing.T) {
+	tests := []struct {
+		ids   []int
+		valid bool
+	}{
+		{nil, false},
+		{[]int{}, false},
+		{[]int{1, 0}, false},
+		{[]int{-1}, false},
+		{[]int{1, 2, 3}, true},
+	}
+
+	for _, tt := range tests {
+		if err := validateIDs(tt.ids); (err == nil) != tt.valid {
+			t.Errorf("validateIDs(%v) returned %v, want valid = %v", tt.ids, err, tt.valid)
+		}
+	}
+}
+
+func TestChunkIDs(t *testing.T) {
+	ids := make([]int, 2*MaxIDsPerRequest+1)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+
+	chunks := chunkIDs(ids)
+	if len(chunks) != 3 {
+		t.Fatalf("chunkIDs returned %d chunks, want 3", len(chunks))
+	}
+	if len(chunks[0]) != MaxIDsPerRequest || len(chunks[1]) != MaxIDsPerRequest {
+		t.Errorf("chunkIDs returned chunks of %d and %d IDs, want %d", len(chunks[0]), len(chunks[1]), MaxIDsPerRequest)
+	}
+	if want := []int{len(ids)}; !reflect.DeepEqual(chunks[2], want) {
+		t.Errorf("chunkIDs last chunk = %v, want %v", chunks[2], want)
+	}
+}
+
+func TestJoinIDs(t *testing.T) {
+	if got, want := joinIDs([]int{1, 22, 333}), "1,22,333"; got != want {
+		t.Errorf("joinIDs returned %q, want %q", got, want)
+	}
+}
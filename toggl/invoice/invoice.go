@@ -0,0 +1,307 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package invoice builds client invoices from Toggl time entries.
+
+An invoice has a line item per project, or per task with Options.ByTask,
+and hourly rate. Rates are resolved with package rates and durations are
+rounded as configured in the workspace unless Options override it:
+
+	inv, err := invoice.Generate(ctx, c, clientID, start, end, nil)
+	if err != nil {
+		return err
+	}
+	err = inv.WriteHTML(w, nil)
+
+Build creates an invoice from data fetched beforehand, without network
+access.
+*/
+package invoice
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+	"github.com/gedex/go-toggl/toggl/rates"
+)
+
+// RoundMode tells how durations are rounded.
+type RoundMode int
+
+// Round modes, matching toggl.Workspace.Rounding.
+const (
+	RoundDown    RoundMode = -1
+	RoundNearest RoundMode = 0
+	RoundUp      RoundMode = 1
+)
+
+// Options configures how invoices are built.
+type Options struct {
+	// One line item per task instead of per project
+	ByTask bool
+
+	// Include non-billable time entries, at an amount of 0
+	IncludeNonBillable bool
+
+	// Round the duration of each time entry to a multiple of RoundTo.
+	// When 0, the workspace's rounding settings apply.
+	RoundTo   time.Duration
+	RoundMode RoundMode
+
+	// Time running entries are measured up to. Defaults to time.Now().
+	Now time.Time
+}
+
+// Data holds the Toggl objects an invoice is built from.
+type Data struct {
+	Client       toggl.WorkspaceClient
+	Workspace    toggl.Workspace
+	Projects     []toggl.Project
+	Tasks        []toggl.Task
+	ProjectUsers []toggl.ProjectUser
+	TimeEntries  []toggl.TimeEntry
+}
+
+// Invoice represents an invoice for a client.
+type Invoice struct {
+	Client   string    `json:"client"`
+	ClientID int       `json:"client_id"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Currency string    `json:"currency"`
+	Lines    []Line    `json:"lines"`
+	Hours    float64   `json:"hours"`
+	Total    float64   `json:"total"`
+}
+
+// Line represents a line item of an invoice.
+type Line struct {
+	ProjectID int    `json:"project_id"`
+	Project   string `json:"project"`
+	TaskID    int    `json:"task_id,omitempty"`
+	Task      string `json:"task,omitempty"`
+
+	// Rounded duration of the line's time entries
+	Duration time.Duration `json:"-"`
+	Hours    float64       `json:"hours"`
+
+	Rate      float64     `json:"rate"`
+	RateLevel rates.Level `json:"-"`
+	Amount    float64     `json:"amount"`
+}
+
+// Description returns the project name, followed by the task name if
+// any.
+func (l *Line) Description() string {
+	if l.Task == "" {
+		return l.Project
+	}
+	return l.Project + " / " + l.Task
+}
+
+// Build builds the invoice of d.Client for time entries started between
+// start and end. Only entries of d.Projects are included. opts may be nil.
+func Build(d *Data, start, end time.Time, opts *Options) (*Invoice, error) {
+	if d == nil {
+		return nil, errors.New("Data cannot be nil")
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	roundTo, mode := opts.RoundTo, opts.RoundMode
+	if roundTo == 0 {
+		roundTo = time.Duration(d.Workspace.RoundingMinutes) * time.Minute
+		mode = RoundMode(d.Workspace.Rounding)
+	}
+
+	projects := make(map[int]toggl.Project, len(d.Projects))
+	for _, p := range d.Projects {
+		projects[p.ID] = p
+	}
+	tasks := make(map[int]string, len(d.Tasks))
+	for _, t := range d.Tasks {
+		tasks[t.ID] = t.Name
+	}
+
+	resolver := rates.NewResolver(
+		[]toggl.Workspace{d.Workspace},
+		[]toggl.WorkspaceClient{d.Client},
+		d.Projects,
+		d.ProjectUsers,
+	)
+
+	inv := &Invoice{
+		Client:   d.Client.Name,
+		ClientID: d.Client.ID,
+		Start:    start,
+		End:      end,
+	}
+
+	type lineKey struct {
+		pid, tid int
+		rate     float64
+	}
+	lines := make(map[lineKey]*Line)
+
+	for i := range d.TimeEntries {
+		te := &d.TimeEntries[i]
+		p, ok := projects[te.ProjectID]
+		if !ok || te.Start == nil || te.Start.Before(start) || te.Start.After(end) {
+			continue
+		}
+		if !te.Billable && !opts.IncludeNonBillable {
+			continue
+		}
+
+		rate := resolver.Resolve(te)
+		if inv.Currency == "" {
+			inv.Currency = rate.Currency
+		}
+		if !te.Billable {
+			rate.Hourly, rate.Level = 0, rates.None
+		}
+
+		k := lineKey{pid: p.ID, rate: rate.Hourly}
+		if opts.ByTask {
+			k.tid = te.TaskID
+		}
+		l := lines[k]
+		if l == nil {
+			l = &Line{
+				ProjectID: p.ID,
+				Project:   p.Name,
+				TaskID:    k.tid,
+				Task:      tasks[k.tid],
+				Rate:      rate.Hourly,
+				RateLevel: rate.Level,
+			}
+			lines[k] = l
+		}
+		l.Duration += Round(te.Elapsed(now), roundTo, mode)
+	}
+
+	for _, l := range lines {
+		l.Hours = l.Duration.Hours()
+		l.Amount = rates.Cost(l.Rate, l.Duration, inv.Currency)
+		inv.Lines = append(inv.Lines, *l)
+		inv.Hours += l.Hours
+		inv.Total += l.Amount
+	}
+	inv.Total = rates.Round(inv.Total, inv.Currency)
+
+	sort.Slice(inv.Lines, func(i, j int) bool {
+		a, b := inv.Lines[i], inv.Lines[j]
+		if a.Description() != b.Description() {
+			return a.Description() < b.Description()
+		}
+		return a.Rate > b.Rate
+	})
+	return inv, nil
+}
+
+// Round rounds d to a multiple of to in the given mode. d is returned
+// unchanged if to is not positive.
+func Round(d, to time.Duration, mode RoundMode) time.Duration {
+	if to <= 0 {
+		return d
+	}
+	switch mode {
+	case RoundDown:
+		return d.Truncate(to)
+	case RoundUp:
+		if r := d.Truncate(to); r != d {
+			return r + to
+		}
+		return d
+	}
+	return d.Round(to)
+}
+
+// Fetch fetches the data for an invoice of the client with the given ID
+// covering time entries started between start and end.
+func Fetch(ctx context.Context, c *toggl.Client, clientID int, start, end time.Time) (*Data, error) {
+	client, err := c.Clients.GetContext(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, errors.New("client not found")
+	}
+	d := &Data{Client: *client}
+
+	workspaces, err := c.Workspaces.ListContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range workspaces {
+		if w.ID == client.WorkspaceID {
+			d.Workspace = w
+		}
+	}
+
+	if d.Projects, err = c.Clients.ListClientProjectsContext(ctx, clientID); err != nil {
+		return nil, err
+	}
+	pids := make(map[int]bool, len(d.Projects))
+	for _, p := range d.Projects {
+		pids[p.ID] = true
+	}
+
+	tasks, err := c.Workspaces.ListTasksContext(ctx, client.WorkspaceID, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		if pids[t.ProjectID] {
+			d.Tasks = append(d.Tasks, t)
+		}
+	}
+
+	it := c.TimeEntries.ListIterContext(ctx, start, end)
+	for it.Next() {
+		if te := it.Entry(); pids[te.ProjectID] {
+			d.TimeEntries = append(d.TimeEntries, te)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	// Project users only matter for their rates, so skip the projects
+	// without time entries rather than asking Toggl about each project.
+	billed := make(map[int]bool)
+	for _, te := range d.TimeEntries {
+		if billed[te.ProjectID] {
+			continue
+		}
+		billed[te.ProjectID] = true
+
+		pus, err := c.Projects.ProjectUsersContext(ctx, te.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		d.ProjectUsers = append(d.ProjectUsers, pus...)
+	}
+
+	return d, nil
+}
+
+// Generate fetches the data for an invoice of the client with the given
+// ID and builds the invoice.
+func Generate(ctx context.Context, c *toggl.Client, clientID int, start, end time.Time, opts *Options) (*Invoice, error) {
+	d, err := Fetch(ctx, c, clientID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return Build(d, start, end, opts)
+}
@@ -0,0 +1,163 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package invoice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+	"github.com/gedex/go-toggl/toggl/rates"
+)
+
+var (
+	start = time.Date(2013, time.July, 1, 0, 0, 0, 0, time.UTC)
+	end   = time.Date(2013, time.July, 31, 23, 59, 59, 0, time.UTC)
+)
+
+func at(day int) *time.Time {
+	t := time.Date(2013, time.July, day, 9, 0, 0, 0, time.UTC)
+	return &t
+}
+
+var data = &Data{
+	Client:    toggl.WorkspaceClient{ID: 10, WorkspaceID: 1, Name: "Acme", HourlyRate: 100, Currency: "EUR"},
+	Workspace: toggl.Workspace{ID: 1, DefaultCurrency: "USD", RoundingMinutes: 15, Rounding: 1},
+	Projects: []toggl.Project{
+		{ID: 100, ClientID: 10, Name: "Website"},
+		{ID: 101, ClientID: 10, Name: "App", Rate: 120},
+	},
+	Tasks:        []toggl.Task{{ID: 1000, ProjectID: 100, Name: "QA"}},
+	ProjectUsers: []toggl.ProjectUser{{ID: 5, ProjectID: 100, UserID: 7, Rate: 150}},
+	TimeEntries: []toggl.TimeEntry{
+		{ProjectID: 100, UserID: 6, Start: at(1), Duration: 3000, Billable: true},               // 50m -> 1h
+		{ProjectID: 100, UserID: 6, TaskID: 1000, Start: at(2), Duration: 1800, Billable: true}, // 30m
+		{ProjectID: 100, UserID: 7, Start: at(3), Duration: 3600, Billable: true},               // 1h at 150
+		{ProjectID: 101, UserID: 6, Start: at(4), Duration: 5400, Billable: true},               // 1h30m at 120
+		{ProjectID: 101, UserID: 6, Start: at(5), Duration: 3600},                               // non-billable
+		{ProjectID: 102, UserID: 6, Start: at(6), Duration: 3600, Billable: true},               // other client
+		{ProjectID: 100, UserID: 6, Start: &time.Time{}, Duration: 3600, Billable: true},        // out of range
+	},
+}
+
+func TestBuild(t *testing.T) {
+	inv, err := Build(data, start, end, nil)
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	want := &Invoice{
+		Client:   "Acme",
+		ClientID: 10,
+		Start:    start,
+		End:      end,
+		Currency: "EUR",
+		Lines: []Line{
+			{ProjectID: 101, Project: "App", Duration: 90 * time.Minute, Hours: 1.5, Rate: 120, RateLevel: rates.Project, Amount: 180},
+			{ProjectID: 100, Project: "Website", Duration: time.Hour, Hours: 1, Rate: 150, RateLevel: rates.ProjectUser, Amount: 150},
+			{ProjectID: 100, Project: "Website", Duration: 90 * time.Minute, Hours: 1.5, Rate: 100, RateLevel: rates.Client, Amount: 150},
+		},
+		Hours: 4,
+		Total: 480,
+	}
+	if !reflect.DeepEqual(inv, want) {
+		t.Errorf("Build returned %+v, want %+v", inv, want)
+	}
+}
+
+func TestBuild_byTaskWithoutRounding(t *testing.T) {
+	inv, err := Build(data, start, end, &Options{
+		ByTask:             true,
+		IncludeNonBillable: true,
+		RoundTo:            time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	var got []string
+	for _, l := range inv.Lines {
+		got = append(got, fmt.Sprintf("%s %v %v %v", l.Description(), l.Duration, l.Rate, l.Amount))
+	}
+	want := []string{
+		"App 1h30m0s 120 180",
+		"App 1h0m0s 0 0",
+		"Website 1h0m0s 150 150",
+		"Website 50m0s 100 83.33",
+		"Website / QA 30m0s 100 50",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Build returned lines %q, want %q", got, want)
+	}
+	if inv.Total != 463.33 {
+		t.Errorf("Build returned total %v, want 463.33", inv.Total)
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		d, to time.Duration
+		mode  RoundMode
+		want  time.Duration
+	}{
+		{50 * time.Minute, 15 * time.Minute, RoundUp, time.Hour},
+		{45 * time.Minute, 15 * time.Minute, RoundUp, 45 * time.Minute},
+		{50 * time.Minute, 15 * time.Minute, RoundDown, 45 * time.Minute},
+		{52 * time.Minute, 15 * time.Minute, RoundNearest, 45 * time.Minute},
+		{53 * time.Minute, 15 * time.Minute, RoundNearest, time.Hour},
+		{53 * time.Minute, 0, RoundUp, 53 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := Round(tt.d, tt.to, tt.mode); got != tt.want {
+			t.Errorf("Round(%v, %v, %v) = %v, want %v", tt.d, tt.to, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	responses := map[string]string{
+		"/clients/10":                 `{"data": {"id": 10, "wid": 1, "name": "Acme", "hrate": 100, "cur": "EUR"}}`,
+		"/workspaces":                 `[{"id": 1, "default_currency": "USD"}]`,
+		"/clients/10/projects":        `[{"id": 100, "cid": 10, "name": "Website"}, {"id": 101, "cid": 10, "name": "Idle"}]`,
+		"/projects/100/project_users": `[{"id": 5, "pid": 100, "uid": 7, "rate": 150}]`,
+		"/workspaces/1/tasks":         `[{"id": 1000, "pid": 100, "name": "QA"}, {"id": 1001, "pid": 200, "name": "Other"}]`,
+		"/time_entries":               `[{"pid": 100, "uid": 7, "start": "2013-07-01T09:00:00Z", "duration": 3600, "billable": true}, {"pid": 200, "start": "2013-07-01T09:00:00Z", "duration": 3600, "billable": true}]`,
+	}
+	for path, body := range responses {
+		body := body
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		})
+	}
+	mux.HandleFunc("/projects/101/project_users", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Generate fetched the users of a project without time entries")
+		fmt.Fprint(w, `[]`)
+	})
+
+	u, _ := url.Parse(server.URL)
+	c := toggl.NewClient("", toggl.WithBaseURL(u))
+
+	inv, err := Generate(context.Background(), c, 10, start, end, nil)
+	if err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	want := []Line{{ProjectID: 100, Project: "Website", Duration: time.Hour, Hours: 1, Rate: 150, RateLevel: rates.ProjectUser, Amount: 150}}
+	if !reflect.DeepEqual(inv.Lines, want) || inv.Currency != "EUR" || inv.Total != 150 {
+		t.Errorf("Generate returned %+v, want lines %+v totalling 150 EUR", inv, want)
+	}
+}
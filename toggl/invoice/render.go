@@ -0,0 +1,95 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package invoice
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"html/template"
+	"io"
+	"strconv"
+
+	"github.com/gedex/go-toggl/toggl/rates"
+)
+
+// WriteCSV writes the invoice's line items as CSV, followed by a total row.
+func (inv *Invoice) WriteCSV(w io.Writer) error {
+	prec := rates.MinorUnits(inv.Currency)
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Project", "Task", "Hours", "Rate", "Amount", "Currency"})
+	for _, l := range inv.Lines {
+		cw.Write([]string{
+			l.Project,
+			l.Task,
+			formatFloat(l.Hours, 2),
+			formatFloat(l.Rate, prec),
+			formatFloat(l.Amount, prec),
+			inv.Currency,
+		})
+	}
+	cw.Write([]string{"Total", "", formatFloat(inv.Hours, 2), "", formatFloat(inv.Total, prec), inv.Currency})
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the invoice as indented JSON.
+func (inv *Invoice) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(inv)
+}
+
+// WriteHTML renders the invoice with tmpl, or DefaultHTMLTemplate if tmpl
+// is nil. The template is executed with the *Invoice as data.
+func (inv *Invoice) WriteHTML(w io.Writer, tmpl *template.Template) error {
+	if tmpl == nil {
+		tmpl = DefaultHTMLTemplate
+	}
+	return tmpl.Execute(w, inv)
+}
+
+func formatFloat(f float64, prec int) string {
+	return strconv.FormatFloat(f, 'f', prec, 64)
+}
+
+// DefaultHTMLTemplate is a simple HTML page listing the invoice's line
+// items. Its "money" function formats an amount with the decimal places of
+// a currency, and "hours" formats durations.
+var DefaultHTMLTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"money": func(f float64, currency string) string { return formatFloat(f, rates.MinorUnits(currency)) },
+	"hours": func(f float64) string { return formatFloat(f, 2) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.Client}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { padding: 4px 12px; text-align: left; }
+td.num, th.num { text-align: right; }
+tfoot td { border-top: 1px solid #000; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Invoice</h1>
+<p>{{.Client}}<br>{{.Start.Format "2006-01-02"}} &ndash; {{.End.Format "2006-01-02"}}</p>
+<table>
+<thead>
+<tr><th>Description</th><th class="num">Hours</th><th class="num">Rate</th><th class="num">Amount</th></tr>
+</thead>
+<tbody>
+{{- range .Lines}}
+<tr><td>{{.Description}}</td><td class="num">{{hours .Hours}}</td><td class="num">{{money .Rate $.Currency}}</td><td class="num">{{money .Amount $.Currency}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot>
+<tr><td>Total</td><td class="num">{{hours .Hours}}</td><td></td><td class="num">{{money .Total .Currency}} {{.Currency}}</td></tr>
+</tfoot>
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,114 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package invoice
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var invoice = &Invoice{
+	Client:   "Acme & Co",
+	ClientID: 10,
+	Start:    time.Date(2013, time.July, 1, 0, 0, 0, 0, time.UTC),
+	End:      time.Date(2013, time.July, 31, 0, 0, 0, 0, time.UTC),
+	Currency: "EUR",
+	Lines: []Line{
+		{ProjectID: 100, Project: "Website", TaskID: 1000, Task: "QA", Duration: 90 * time.Minute, Hours: 1.5, Rate: 100, Amount: 150},
+	},
+	Hours: 1.5,
+	Total: 150,
+}
+
+func TestInvoice_WriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := invoice.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV returned error: %v", err)
+	}
+
+	want := "Project,Task,Hours,Rate,Amount,Currency\n" +
+		"Website,QA,1.50,100.00,150.00,EUR\n" +
+		"Total,,1.50,,150.00,EUR\n"
+	if buf.String() != want {
+		t.Errorf("WriteCSV wrote %q, want %q", buf.String(), want)
+	}
+}
+
+func TestInvoice_minorUnits(t *testing.T) {
+	inv := &Invoice{
+		Currency: "JPY",
+		Lines:    []Line{{Project: "Website", Hours: 1.5, Rate: 8000, Amount: 12000}},
+		Hours:    1.5,
+		Total:    12000,
+	}
+	var buf bytes.Buffer
+	if err := inv.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV returned error: %v", err)
+	}
+
+	want := "Project,Task,Hours,Rate,Amount,Currency\n" +
+		"Website,,1.50,8000,12000,JPY\n" +
+		"Total,,1.50,,12000,JPY\n"
+	if buf.String() != want {
+		t.Errorf("WriteCSV wrote %q, want %q", buf.String(), want)
+	}
+
+	inv.Currency, inv.Lines[0].Amount, inv.Total = "KWD", 12.3456, 12.3456
+	buf.Reset()
+	if err := inv.WriteHTML(&buf, nil); err != nil {
+		t.Fatalf("WriteHTML returned error: %v", err)
+	}
+	if s := "12.346 KWD"; !strings.Contains(buf.String(), s) {
+		t.Errorf("WriteHTML output does not contain %q:\n%s", s, buf.String())
+	}
+}
+
+func TestInvoice_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := invoice.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON returned error: %v", err)
+	}
+
+	got := new(Invoice)
+	if err := json.Unmarshal(buf.Bytes(), got); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+
+	// Durations are represented by hours only.
+	want := *invoice
+	want.Lines = []Line{invoice.Lines[0]}
+	want.Lines[0].Duration = 0
+	if !reflect.DeepEqual(got, &want) {
+		t.Errorf("WriteJSON round trip = %+v, want %+v", got, &want)
+	}
+}
+
+func TestInvoice_WriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := invoice.WriteHTML(&buf, nil); err != nil {
+		t.Fatalf("WriteHTML returned error: %v", err)
+	}
+
+	for _, s := range []string{"Acme &amp; Co", "2013-07-01", "Website / QA", "150.00 EUR"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("WriteHTML output does not contain %q:\n%s", s, buf.String())
+		}
+	}
+
+	buf.Reset()
+	tmpl := template.Must(template.New("t").Parse(`{{.Client}}: {{.Total}}`))
+	if err := invoice.WriteHTML(&buf, tmpl); err != nil {
+		t.Fatalf("WriteHTML returned error: %v", err)
+	}
+	if want := "Acme &amp; Co: 150"; buf.String() != want {
+		t.Errorf("WriteHTML with template wrote %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	l := NewRateLimiter(100, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait returned error: %v", err)
+		}
+	}
+
+	// Two requests fit in the burst, the other two wait 10ms each.
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("Wait let 4 requests through in %v, want at least 20ms", elapsed)
+	}
+}
+
+func TestRateLimiter_WaitCanceled(t *testing.T) {
+	l := NewRateLimiter(0.001, 1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait returned error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRateLimiter_unlimited(t *testing.T) {
+	l := NewRateLimiter(0, 1)
+	for i := 0; i < 100; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait returned error: %v", err)
+		}
+	}
+}
+
+func TestDo_rateLimiter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.RateLimiter = NewRateLimiter(0.001, 1)
+
+	mux.HandleFunc("/time_entries/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id": 1}}`)
+	})
+
+	if _, err := client.TimeEntries.Get(1); err != nil {
+		t.Fatalf("TimeEntries.Get returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.TimeEntries.GetContext(ctx, 1); err != context.DeadlineExceeded {
+		t.Errorf("TimeEntries.GetContext returned error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package offline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+// OpError reports a queued operation that failed to replay. The operation
+// stays at the head of the queue until it succeeds or is discarded.
+type OpError struct {
+	Op  Op
+	Err error
+}
+
+func (e *OpError) Error() string {
+	return fmt.Sprintf("offline: %s of time entry %s: %v", e.Op.Kind, e.Op.name(), e.Err)
+}
+
+// name identifies the time entry of an operation in errors.
+func (op *Op) name() string {
+	if op.ID > 0 {
+		return fmt.Sprint(op.ID)
+	}
+	return op.GUID
+}
+
+// Flush replays the queued operations in order, stopping at the first one
+// that fails with an *OpError.
+func (q *Queue) Flush() error {
+	return q.FlushContext(context.Background())
+}
+
+// FlushContext is like Flush, but with the provided context.
+func (q *Queue) FlushContext(ctx context.Context) error {
+	q.flushMu.Lock()
+	defer q.flushMu.Unlock()
+
+	for {
+		// Operations may be queued while the head one is replayed, but
+		// only Flush and Discard remove them.
+		q.mu.Lock()
+		if len(q.st.Ops) == 0 {
+			q.mu.Unlock()
+			return nil
+		}
+		op := q.st.Ops[0]
+		q.st.Ops[0].Attempted = true
+		err := q.save()
+		q.mu.Unlock()
+		if err != nil {
+			return err
+		}
+
+		id, err := q.replay(ctx, &op)
+
+		q.mu.Lock()
+		if err != nil {
+			if !isTransient(err) && len(q.st.Ops) > 0 && q.st.Ops[0].Seq == op.Seq {
+				// Toggl refused the operation, so it was not applied.
+				q.st.Ops[0].Attempted = false
+				if err := q.save(); err != nil {
+					q.mu.Unlock()
+					return err
+				}
+			}
+			q.mu.Unlock()
+			return &OpError{Op: op, Err: err}
+		}
+		if len(q.st.Ops) > 0 && q.st.Ops[0].Seq == op.Seq {
+			q.st.Ops = q.st.Ops[1:]
+		}
+		if id > 0 && op.GUID != "" {
+			q.reconcile(op.GUID, id)
+		}
+		q.prune()
+		err = q.save()
+		q.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// reconcile records the Toggl ID of a created time entry in the queued
+// operations on it.
+func (q *Queue) reconcile(guid string, id int) {
+	q.st.IDs[guid] = id
+	for i := range q.st.Ops {
+		if q.st.Ops[i].GUID == guid {
+			q.st.Ops[i].ID = id
+		}
+	}
+	if e, ok := q.st.Entries[guid]; ok {
+		e.ID = id
+		q.st.Entries[guid] = e
+	}
+}
+
+// replay sends an operation to Toggl, and returns the ID of the time entry
+// it created, if any.
+func (q *Queue) replay(ctx context.Context, op *Op) (int, error) {
+	s := q.client.TimeEntries
+
+	switch op.Kind {
+	case OpStart, OpCreate:
+		if op.Attempted {
+			// The entry may have been created without a response reaching
+			// us; don't create it twice.
+			if id, err := q.find(ctx, op.TimeEntry); err != nil || id > 0 {
+				return id, err
+			}
+		}
+		te := *op.TimeEntry
+		te.ID = 0
+		created, err := s.CreateContext(ctx, &te)
+		if err != nil {
+			return 0, err
+		}
+		if created == nil {
+			return 0, errors.New("no time entry returned")
+		}
+		return created.ID, nil
+
+	case OpStop, OpUpdate:
+		if op.ID <= 0 {
+			return 0, errors.New("time entry was never created")
+		}
+		te := *op.TimeEntry
+		te.ID = op.ID
+		_, err := s.UpdateContext(ctx, &te)
+		return 0, err
+
+	case OpDelete:
+		if op.ID <= 0 {
+			return 0, errors.New("time entry was never created")
+		}
+		err := s.DeleteContext(ctx, op.ID)
+		if toggl.IsNotFound(err) {
+			err = nil
+		}
+		return 0, err
+	}
+	return 0, fmt.Errorf("unknown operation %q", op.Kind)
+}
+
+// find returns the ID of the time entry on Toggl with the GUID of te, or 0.
+func (q *Queue) find(ctx context.Context, te *toggl.TimeEntry) (int, error) {
+	start, end := te.Start.Add(-time.Minute), te.Start.Add(time.Minute)
+	list, err := q.client.TimeEntries.ListContext(ctx, &start, &end)
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range list {
+		if e.GUID == te.GUID {
+			return e.ID, nil
+		}
+	}
+	return 0, nil
+}
+
+// isTransient reports whether err may have happened after Toggl applied
+// the request, e.g. because the connection was lost.
+func isTransient(err error) bool {
+	var e *toggl.ErrorResponse
+	if !errors.As(err, &e) {
+		return true
+	}
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
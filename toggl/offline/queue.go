@@ -0,0 +1,385 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package offline tracks time while Toggl cannot be reached.
+
+A Queue records time entry operations in a local file instead of sending
+them, and replays them in order on Flush:
+
+	q, err := offline.Open("toggl-queue.json", client)
+	...
+	te, err := q.Start(&toggl.TimeEntry{Description: "Site visit", WorkspaceID: wid})
+	...
+	te, err = q.Stop()
+	...
+	if err := q.Flush(); err != nil {
+		// Still offline, try again later.
+	}
+
+Time entries created through a Queue get a client-side GUID, by which
+later operations refer to them until Toggl assigns an ID. Once an entry is
+created on Toggl, its ID is recorded in the queued operations and can be
+looked up with Queue.ID.
+
+Starting and stopping timers use the local clock rather than the time the
+operations are replayed.
+*/
+package offline
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+	"github.com/gedex/go-toggl/toggl/internal/fileutil"
+)
+
+// OpKind is the kind of a queued operation.
+type OpKind string
+
+// Kinds of queued operations.
+const (
+	OpStart  OpKind = "start"
+	OpStop   OpKind = "stop"
+	OpCreate OpKind = "create"
+	OpUpdate OpKind = "update"
+	OpDelete OpKind = "delete"
+)
+
+// Op is a queued time entry operation.
+type Op struct {
+	// Position of the operation in the queue
+	Seq int `json:"seq"`
+
+	Kind OpKind `json:"kind"`
+
+	// Time entry the operation applies to, by GUID or Toggl ID. ID is
+	// filled in once the time entry is created on Toggl.
+	GUID string `json:"guid,omitempty"`
+	ID   int    `json:"id,omitempty"`
+
+	// Time entry data sent to Toggl, nil for OpDelete
+	TimeEntry *toggl.TimeEntry `json:"time_entry,omitempty"`
+
+	// When the operation was queued
+	Queued time.Time `json:"queued"`
+
+	// Whether the operation was sent without getting a response, in which
+	// case it may have been applied already.
+	Attempted bool `json:"attempted,omitempty"`
+}
+
+// state is the content of a queue file.
+type state struct {
+	Seq int  `json:"seq"`
+	Ops []Op `json:"ops"`
+
+	// Toggl IDs of time entries by GUID
+	IDs map[string]int `json:"ids"`
+
+	// Local state of the time entries with queued operations, by GUID
+	Entries map[string]toggl.TimeEntry `json:"entries"`
+
+	// GUID of the running time entry started through the queue
+	Running string `json:"running,omitempty"`
+}
+
+// Queue records time entry operations and replays them on Toggl. It is
+// safe for concurrent use.
+type Queue struct {
+	client *toggl.Client
+	path   string
+	now    func() time.Time
+
+	mu sync.Mutex
+	st state
+
+	flushMu sync.Mutex
+}
+
+// Open returns a Queue stored in the file at path, replaying operations
+// through c. The file is created on the first operation if it does not
+// exist.
+func Open(path string, c *toggl.Client) (*Queue, error) {
+	q := &Queue{client: c, path: path, now: time.Now}
+
+	b, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(b, &q.st); err != nil {
+			return nil, fmt.Errorf("offline: reading %s: %v", path, err)
+		}
+	}
+	if q.st.IDs == nil {
+		q.st.IDs = make(map[string]int)
+	}
+	if q.st.Entries == nil {
+		q.st.Entries = make(map[string]toggl.TimeEntry)
+	}
+	return q, nil
+}
+
+// NewGUID returns a random GUID for a time entry.
+func NewGUID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// Pending returns the queued operations, in order.
+func (q *Queue) Pending() []Op {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]Op(nil), q.st.Ops...)
+}
+
+// ID returns the Toggl ID of the time entry with the given GUID, and
+// whether it is known.
+func (q *Queue) ID(guid string) (int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	id, ok := q.st.IDs[guid]
+	return id, ok
+}
+
+// Current returns the local state of the running time entry started
+// through the queue, or nil.
+func (q *Queue) Current() *toggl.TimeEntry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.st.Running == "" {
+		return nil
+	}
+	te := q.st.Entries[q.st.Running]
+	return &te
+}
+
+// Start queues starting a time entry now, and returns its local state.
+// The running time entry started through the queue, if any, is stopped.
+func (q *Queue) Start(te *toggl.TimeEntry) (*toggl.TimeEntry, error) {
+	if te == nil {
+		return nil, errors.New("TimeEntry cannot be nil")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now().Truncate(time.Second)
+	if q.st.Running != "" {
+		q.stop(now)
+	}
+
+	e := q.newEntry(te)
+	e.Start, e.Stop, e.Duration = &now, nil, int(-now.Unix())
+	q.st.Entries[e.GUID] = e
+	q.st.Running = e.GUID
+	q.push(OpStart, e.GUID, 0, &e)
+	return &e, q.save()
+}
+
+// Stop queues stopping the running time entry started through the queue
+// now, and returns its local state.
+func (q *Queue) Stop() (*toggl.TimeEntry, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.st.Running == "" {
+		return nil, errors.New("offline: no running time entry")
+	}
+	e := q.stop(q.now().Truncate(time.Second))
+	return &e, q.save()
+}
+
+// stop queues stopping the running time entry at t.
+func (q *Queue) stop(t time.Time) toggl.TimeEntry {
+	e := q.st.Entries[q.st.Running]
+	e.Stop = &t
+	e.Duration = int(t.Sub(*e.Start) / time.Second)
+	q.st.Entries[e.GUID] = e
+	q.st.Running = ""
+	q.push(OpStop, e.GUID, q.st.IDs[e.GUID], &toggl.TimeEntry{Stop: e.Stop, Duration: e.Duration})
+	return e
+}
+
+// Create queues creating a time entry, and returns its local state.
+func (q *Queue) Create(te *toggl.TimeEntry) (*toggl.TimeEntry, error) {
+	if te == nil {
+		return nil, errors.New("TimeEntry cannot be nil")
+	}
+	if te.Start == nil {
+		return nil, errors.New("TimeEntry.Start cannot be nil")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	e := q.newEntry(te)
+	q.st.Entries[e.GUID] = e
+	q.push(OpCreate, e.GUID, 0, &e)
+	return &e, q.save()
+}
+
+// Update queues updating a time entry identified by its GUID or ID. Only
+// the non-zero fields of te are changed, as with TimeEntriesService.Update.
+func (q *Queue) Update(te *toggl.TimeEntry) error {
+	if te == nil {
+		return errors.New("TimeEntry cannot be nil")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	guid, id, err := q.resolve(te)
+	if err != nil {
+		return err
+	}
+
+	changes := *te
+	changes.ID, changes.GUID = 0, ""
+	if e, ok := q.st.Entries[guid]; ok {
+		b, err := json.Marshal(changes)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(b, &e); err != nil {
+			return err
+		}
+		q.st.Entries[guid] = e
+		if guid == q.st.Running && !e.IsRunning() {
+			q.st.Running = ""
+		}
+	}
+	q.push(OpUpdate, guid, id, &changes)
+	return q.save()
+}
+
+// Delete queues deleting a time entry identified by its GUID or ID.
+func (q *Queue) Delete(te *toggl.TimeEntry) error {
+	if te == nil {
+		return errors.New("TimeEntry cannot be nil")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	guid, id, err := q.resolve(te)
+	if err != nil {
+		return err
+	}
+	if guid == q.st.Running {
+		q.st.Running = ""
+	}
+	q.push(OpDelete, guid, id, nil)
+	return q.save()
+}
+
+// Discard removes the queued operation with the given Seq, typically one
+// Toggl refuses. Discarding a start or create also discards the later
+// operations on its time entry.
+func (q *Queue) Discard(seq int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var guid string
+	ops := q.st.Ops[:0]
+	for _, op := range q.st.Ops {
+		switch {
+		case op.Seq == seq:
+			if (op.Kind == OpStart || op.Kind == OpCreate) && op.GUID != "" {
+				guid = op.GUID
+			}
+			continue
+		case guid != "" && op.GUID == guid:
+			continue
+		}
+		ops = append(ops, op)
+	}
+	if len(ops) == len(q.st.Ops) {
+		return fmt.Errorf("offline: no queued operation %d", seq)
+	}
+	q.st.Ops = ops
+	if guid != "" && q.st.Running == guid {
+		q.st.Running = ""
+	}
+	q.prune()
+	return q.save()
+}
+
+// newEntry returns the local state of a new time entry, with a GUID.
+func (q *Queue) newEntry(te *toggl.TimeEntry) toggl.TimeEntry {
+	e := *te
+	e.ID = 0
+	if e.GUID == "" {
+		e.GUID = NewGUID()
+	}
+	if e.CreatedWith == "" {
+		e.CreatedWith = toggl.UserAgent
+	}
+	return e
+}
+
+// resolve returns the GUID and Toggl ID of an existing time entry. Either
+// may be unknown, but not both.
+func (q *Queue) resolve(te *toggl.TimeEntry) (string, int, error) {
+	if te.GUID != "" {
+		_, queued := q.st.Entries[te.GUID]
+		id, created := q.st.IDs[te.GUID]
+		if queued || created {
+			return te.GUID, id, nil
+		}
+	}
+	if te.ID > 0 {
+		return te.GUID, te.ID, nil
+	}
+	return "", 0, errors.New("offline: unknown time entry")
+}
+
+// push appends an operation to the queue.
+func (q *Queue) push(kind OpKind, guid string, id int, te *toggl.TimeEntry) {
+	q.st.Seq++
+	q.st.Ops = append(q.st.Ops, Op{
+		Seq:       q.st.Seq,
+		Kind:      kind,
+		GUID:      guid,
+		ID:        id,
+		TimeEntry: te,
+		Queued:    q.now(),
+	})
+}
+
+// prune forgets the local state of time entries without queued operations,
+// other than the running one.
+func (q *Queue) prune() {
+	pending := map[string]bool{q.st.Running: true}
+	for _, op := range q.st.Ops {
+		pending[op.GUID] = true
+	}
+	for guid := range q.st.Entries {
+		if !pending[guid] {
+			delete(q.st.Entries, guid)
+		}
+	}
+}
+
+// save writes the queue to the queue file.
+func (q *Queue) save() error {
+	b, err := json.Marshal(q.st)
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteFile(q.path, b)
+}
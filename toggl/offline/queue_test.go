@@ -0,0 +1,214 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package offline
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+	"github.com/gedex/go-toggl/toggl/toggltest"
+)
+
+func setup(t *testing.T) (*toggltest.Server, string, func()) {
+	s := toggltest.NewServer()
+	dir, err := os.MkdirTemp("", "toggl-offline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, filepath.Join(dir, "queue.json"), func() {
+		s.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func open(t *testing.T, path string, c *toggl.Client, now *time.Time) *Queue {
+	q, err := Open(path, c)
+	if err != nil {
+		t.Fatalf("Open returned error: %v", err)
+	}
+	q.now = func() time.Time { return *now }
+	return q
+}
+
+func kinds(ops []Op) []OpKind {
+	var kinds []OpKind
+	for _, op := range ops {
+		kinds = append(kinds, op.Kind)
+	}
+	return kinds
+}
+
+func TestNewGUID(t *testing.T) {
+	re := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if a, b := NewGUID(), NewGUID(); !re.MatchString(a) || a == b {
+		t.Errorf("NewGUID returned %q and %q", a, b)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	s, path, teardown := setup(t)
+	defer teardown()
+	wid := s.User().DefautWID
+	s.Fail(toggltest.Fault{}) // offline
+
+	now := time.Date(2013, time.July, 1, 9, 0, 0, 0, time.UTC)
+	q := open(t, path, s.Client(), &now)
+
+	first, err := q.Start(&toggl.TimeEntry{WorkspaceID: wid, Description: "Site visit"})
+	if err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	if first.GUID == "" || !first.IsRunning() || !first.Start.Equal(now) {
+		t.Errorf("Start returned %+v", first)
+	}
+
+	now = now.Add(time.Hour)
+	second, err := q.Start(&toggl.TimeEntry{WorkspaceID: wid, Description: "Travel"})
+	if err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	if err := q.Update(&toggl.TimeEntry{GUID: second.GUID, Description: "Travel back"}); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	if got := q.Current(); got.GUID != second.GUID || got.Description != "Travel back" {
+		t.Errorf("Current returned %+v", got)
+	}
+
+	now = now.Add(30 * time.Minute)
+	stopped, err := q.Stop()
+	if err != nil {
+		t.Fatalf("Stop returned error: %v", err)
+	}
+	if stopped.Duration != 1800 || q.Current() != nil {
+		t.Errorf("Stop returned %+v", stopped)
+	}
+	if _, err := q.Stop(); err == nil {
+		t.Errorf("Stop without running time entry returned no error")
+	}
+
+	start := now.Add(-24 * time.Hour)
+	deleted, err := q.Create(&toggl.TimeEntry{WorkspaceID: wid, Start: &start, Duration: 60})
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if err := q.Delete(deleted); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if err := q.Update(&toggl.TimeEntry{GUID: "unknown"}); err == nil {
+		t.Errorf("Update of unknown time entry returned no error")
+	}
+
+	want := []OpKind{OpStart, OpStop, OpStart, OpUpdate, OpStop, OpCreate, OpDelete}
+	if got := kinds(q.Pending()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Pending returned %v, want %v", got, want)
+	}
+
+	var opErr *OpError
+	if err := q.Flush(); !errors.As(err, &opErr) || opErr.Op.Kind != OpStart {
+		t.Fatalf("Flush while offline returned %v, want *OpError", err)
+	}
+	if len(q.Pending()) != len(want) {
+		t.Errorf("Flush while offline removed operations")
+	}
+
+	// Back online, after a restart.
+	s.ClearFaults()
+	q = open(t, path, s.Client(), &now)
+	if err := q.Flush(); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+	if n := len(q.Pending()); n != 0 {
+		t.Errorf("Flush left %d operations", n)
+	}
+
+	entries := s.TimeEntries()
+	if len(entries) != 2 {
+		t.Fatalf("Server has time entries %+v, want 2", entries)
+	}
+	for i, want := range []*toggl.TimeEntry{first, second} {
+		got := entries[i]
+		id, ok := q.ID(want.GUID)
+		if !ok || got.ID != id || got.GUID != want.GUID || !got.Start.Equal(*want.Start) || got.IsRunning() {
+			t.Errorf("Server has time entry %+v, want %+v with ID %d", got, want, id)
+		}
+	}
+	if entries[0].Duration != 3600 || entries[1].Duration != 1800 || entries[1].Description != "Travel back" {
+		t.Errorf("Server has time entries %+v", entries)
+	}
+}
+
+func TestQueue_Flush_attempted(t *testing.T) {
+	s, path, teardown := setup(t)
+	defer teardown()
+	wid := s.User().DefautWID
+
+	now := time.Now().Truncate(time.Second)
+	q := open(t, path, s.Client(), &now)
+	te, err := q.Start(&toggl.TimeEntry{WorkspaceID: wid})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The entry was created but the response was lost.
+	created := s.AddTimeEntry(toggl.TimeEntry{WorkspaceID: wid, GUID: te.GUID, Start: te.Start, Duration: te.Duration})
+	q.st.Ops[0].Attempted = true
+
+	if err := q.Flush(); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+	if n := len(s.TimeEntries()); n != 1 {
+		t.Errorf("Flush created the time entry again, server has %d", n)
+	}
+	if id, _ := q.ID(te.GUID); id != created.ID {
+		t.Errorf("ID returned %d, want %d", id, created.ID)
+	}
+}
+
+func TestQueue_Discard(t *testing.T) {
+	s, path, teardown := setup(t)
+	defer teardown()
+
+	now := time.Date(2013, time.July, 1, 9, 0, 0, 0, time.UTC)
+	q := open(t, path, s.Client(), &now)
+	if err := q.Update(&toggl.TimeEntry{ID: 12345, Description: "gone"}); err != nil {
+		t.Fatal(err)
+	}
+	te, err := q.Create(&toggl.TimeEntry{Start: &now, Duration: 60}) // no workspace
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Delete(te); err != nil {
+		t.Fatal(err)
+	}
+
+	var opErr *OpError
+	err = q.Flush()
+	if !errors.As(err, &opErr) || !toggl.IsNotFound(opErr.Err) || q.Pending()[0].Attempted {
+		t.Fatalf("Flush returned %v, want not found *OpError", err)
+	}
+	if err := q.Discard(opErr.Op.Seq); err != nil {
+		t.Fatalf("Discard returned error: %v", err)
+	}
+
+	if err := q.Flush(); !errors.As(err, &opErr) || opErr.Op.Kind != OpCreate {
+		t.Fatalf("Flush returned %v, want create *OpError", err)
+	}
+	if err := q.Discard(opErr.Op.Seq); err != nil {
+		t.Fatalf("Discard returned error: %v", err)
+	}
+	if n := len(q.Pending()); n != 0 {
+		t.Errorf("Discard of create left %d operations", n)
+	}
+	if err := q.Discard(opErr.Op.Seq); err == nil {
+		t.Errorf("Discard of missing operation returned no error")
+	}
+}
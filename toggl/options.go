@@ -0,0 +1,101 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggl
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ClientOption configures a Client created by NewClient.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the HTTP client used to communicate with the API. A
+// nil hc is ignored.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		if hc != nil {
+			c.client = hc
+		}
+	}
+}
+
+// WithTransport sets the transport of the Client's HTTP client, e.g. to
+// configure TLS or proxies. The HTTP client given to WithHTTPClient, or
+// http.DefaultClient, is copied rather than modified.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		hc := *c.client
+		hc.Transport = rt
+		c.client = &hc
+	}
+}
+
+// WithBaseURL sets the base URL for API requests. A trailing slash is
+// added to its path if missing so that relative URLs resolve below it. A
+// nil u is ignored.
+func WithBaseURL(u *url.URL) ClientOption {
+	return func(c *Client) {
+		if u != nil {
+			c.BaseURL = withTrailingSlash(u)
+		}
+	}
+}
+
+// WithReportsBaseURL sets the base URL for Reports API requests. A
+// trailing slash is added to its path if missing. A nil u is ignored.
+func WithReportsBaseURL(u *url.URL) ClientOption {
+	return func(c *Client) {
+		if u != nil {
+			c.ReportsURL = withTrailingSlash(u)
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) ClientOption {
+	return func(c *Client) {
+		c.UserAgent = ua
+	}
+}
+
+// WithAuthenticator sets the credentials added to every request, replacing
+// the API token given to NewClient. A nil a is ignored.
+func WithAuthenticator(a Authenticator) ClientOption {
+	return func(c *Client) {
+		if a != nil {
+			c.auth = a
+		}
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry throttled and failed
+// requests.
+func WithRetryPolicy(p *RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.RetryPolicy = p
+	}
+}
+
+// WithRateLimiter sets the limiter waited on before each request.
+func WithRateLimiter(l *RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.RateLimiter = l
+	}
+}
+
+func withTrailingSlash(u *url.URL) *url.URL {
+	if u.Path == "" || strings.HasSuffix(u.Path, "/") {
+		return u
+	}
+	v := *u
+	v.Path += "/"
+	if v.RawPath != "" {
+		v.RawPath += "/"
+	}
+	return &v
+}
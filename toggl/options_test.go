@@ -0,0 +1,101 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggl
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestNewClient_defaults(t *testing.T) {
+	c := NewClient("token")
+
+	if c.client != http.DefaultClient {
+		t.Errorf("NewClient HTTP client = %v, want http.DefaultClient", c.client)
+	}
+	if c.BaseURL.String() != BaseURL {
+		t.Errorf("NewClient BaseURL = %v, want %v", c.BaseURL, BaseURL)
+	}
+	if c.UserAgent != UserAgent {
+		t.Errorf("NewClient UserAgent = %v, want %v", c.UserAgent, UserAgent)
+	}
+	if c.RetryPolicy != nil || c.RateLimiter != nil {
+		t.Errorf("NewClient enabled retries or rate limiting by default")
+	}
+}
+
+func TestNewClient_options(t *testing.T) {
+	hc := &http.Client{}
+	u, _ := url.Parse("http://localhost:8080/api/v8")
+	p := &RetryPolicy{MaxAttempts: 3}
+	l := NewRateLimiter(1, 1)
+
+	c := NewClient("token",
+		WithHTTPClient(hc),
+		WithBaseURL(u),
+		WithUserAgent("ua"),
+		WithRetryPolicy(p),
+		WithRateLimiter(l),
+	)
+
+	if c.client != hc {
+		t.Errorf("NewClient HTTP client = %v, want %v", c.client, hc)
+	}
+	if want := "http://localhost:8080/api/v8/"; c.BaseURL.String() != want {
+		t.Errorf("NewClient BaseURL = %v, want %v", c.BaseURL, want)
+	}
+	if c.UserAgent != "ua" {
+		t.Errorf("NewClient UserAgent = %v, want %v", c.UserAgent, "ua")
+	}
+	if c.RetryPolicy != p || c.RateLimiter != l {
+		t.Errorf("NewClient did not apply retry policy or rate limiter")
+	}
+
+	req, _ := c.NewRequest("GET", "me", nil)
+	if want := "http://localhost:8080/api/v8/me"; req.URL.String() != want {
+		t.Errorf("NewRequest URL = %v, want %v", req.URL, want)
+	}
+	if got := req.Header.Get("User-Agent"); got != "ua" {
+		t.Errorf("NewRequest User-Agent = %v, want %v", got, "ua")
+	}
+}
+
+func TestNewClient_nilOptions(t *testing.T) {
+	c := NewClient("token", WithHTTPClient(nil), WithBaseURL(nil), WithReportsBaseURL(nil))
+
+	if c.client != http.DefaultClient {
+		t.Errorf("NewClient HTTP client = %v, want http.DefaultClient", c.client)
+	}
+	if c.BaseURL.String() != BaseURL || c.ReportsURL.String() != ReportsBaseURL {
+		t.Errorf("NewClient URLs = %v, %v, want %v, %v", c.BaseURL, c.ReportsURL, BaseURL, ReportsBaseURL)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestWithTransport(t *testing.T) {
+	called := false
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		called = true
+		return nil, http.ErrHandlerTimeout
+	})
+
+	c := NewClient("token", WithTransport(rt))
+	if http.DefaultClient.Transport != nil {
+		t.Fatalf("WithTransport modified http.DefaultClient")
+	}
+
+	req, _ := c.NewRequest("GET", "me", nil)
+	c.Do(req, nil)
+	if !called {
+		t.Errorf("Do did not use the transport given to WithTransport")
+	}
+}
@@ -6,6 +6,7 @@
 package toggl
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -69,9 +70,14 @@ type ProjectUserMassCreate struct {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/project_users.md#create-a-project-user
 func (s *ProjectUsersService) Create(pu *ProjectUser) (*ProjectUser, error) {
+	return s.CreateContext(context.Background(), pu)
+}
+
+// CreateContext is like Create, but with the provided context.
+func (s *ProjectUsersService) CreateContext(ctx context.Context, pu *ProjectUser) (*ProjectUser, error) {
 	u := "project_users"
 	puc := &ProjectUserCreate{ProjectUser: pu}
-	req, err := s.client.NewRequest("POST", u, puc)
+	req, err := s.client.NewRequestContext(ctx, "POST", u, puc)
 	if err != nil {
 		return nil, err
 	}
@@ -86,9 +92,14 @@ func (s *ProjectUsersService) Create(pu *ProjectUser) (*ProjectUser, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/project_users.md#create-multiple-project-users-for-single-project
 func (s *ProjectUsersService) MassCreate(pu *ProjectUserMultipleUserID) ([]ProjectUser, error) {
+	return s.MassCreateContext(context.Background(), pu)
+}
+
+// MassCreateContext is like MassCreate, but with the provided context.
+func (s *ProjectUsersService) MassCreateContext(ctx context.Context, pu *ProjectUserMultipleUserID) ([]ProjectUser, error) {
 	u := "project_users"
 	puc := &ProjectUserMassCreate{ProjectUser: pu}
-	req, err := s.client.NewRequest("POST", u, puc)
+	req, err := s.client.NewRequestContext(ctx, "POST", u, puc)
 	if err != nil {
 		return nil, err
 	}
@@ -99,10 +110,50 @@ func (s *ProjectUsersService) MassCreate(pu *ProjectUserMultipleUserID) ([]Proje
 	return data.Data, err
 }
 
+// MassCreateIDs creates a project user like pu for each of the users
+// uids. More than MaxIDsPerRequest uids are created in several requests.
+// If one of them fails, the project users created so far are returned
+// with the error.
+func (s *ProjectUsersService) MassCreateIDs(uids []int, pu *ProjectUser) ([]ProjectUser, error) {
+	return s.MassCreateIDsContext(context.Background(), uids, pu)
+}
+
+// MassCreateIDsContext is like MassCreateIDs, but with the provided context.
+func (s *ProjectUsersService) MassCreateIDsContext(ctx context.Context, uids []int, pu *ProjectUser) ([]ProjectUser, error) {
+	if pu == nil {
+		return nil, errors.New("ProjectUser cannot be nil")
+	}
+	if err := validateIDs(uids); err != nil {
+		return nil, err
+	}
+
+	var pus []ProjectUser
+	for _, chunk := range chunkIDs(uids) {
+		mpu := &ProjectUserMultipleUserID{
+			ProjectID:   pu.ProjectID,
+			UserID:      joinIDs(chunk),
+			WorkspaceID: pu.WorkspaceID,
+			Manager:     pu.Manager,
+			Rate:        pu.Rate,
+		}
+		data, err := s.MassCreateContext(ctx, mpu)
+		pus = append(pus, data...)
+		if err != nil {
+			return pus, err
+		}
+	}
+	return pus, nil
+}
+
 // Update a project user.
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/project_users.md#update-a-project-user
 func (s *ProjectUsersService) Update(pu *ProjectUser) (*ProjectUser, error) {
+	return s.UpdateContext(context.Background(), pu)
+}
+
+// UpdateContext is like Update, but with the provided context.
+func (s *ProjectUsersService) UpdateContext(ctx context.Context, pu *ProjectUser) (*ProjectUser, error) {
 	if pu == nil {
 		return nil, errors.New("ProjectUser cannot be nil")
 	}
@@ -113,7 +164,7 @@ func (s *ProjectUsersService) Update(pu *ProjectUser) (*ProjectUser, error) {
 	u := fmt.Sprintf("project_users/%v", pu.ID)
 
 	puc := &ProjectUserCreate{pu}
-	req, err := s.client.NewRequest("PUT", u, puc)
+	req, err := s.client.NewRequestContext(ctx, "PUT", u, puc)
 	if err != nil {
 		return nil, err
 	}
@@ -124,14 +175,53 @@ func (s *ProjectUsersService) Update(pu *ProjectUser) (*ProjectUser, error) {
 	return data.Data, err
 }
 
+// UpdateChecked updates a project user, unless it changed since pu was fetched.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/project_users.md#update-a-project-user
+func (s *ProjectUsersService) UpdateChecked(pu *ProjectUser, merge ProjectUserMergeFunc) (*ProjectUser, error) {
+	return s.UpdateCheckedContext(context.Background(), pu, merge)
+}
+
+// UpdateCheckedContext is like UpdateChecked, but with the provided context.
+func (s *ProjectUsersService) UpdateCheckedContext(ctx context.Context, pu *ProjectUser, merge ProjectUserMergeFunc) (*ProjectUser, error) {
+	return updateChecked("ProjectUser", pu, merge,
+		func(x *ProjectUser) (*int, *time.Time) { return &x.ID, x.At },
+		func(x *ProjectUser) (*ProjectUser, error) { return s.current(ctx, x) },
+		func(x *ProjectUser) (*ProjectUser, error) { return s.UpdateContext(ctx, x) })
+}
+
+// current fetches the project user from its project, as Toggl has no
+// endpoint for a single project user.
+func (s *ProjectUsersService) current(ctx context.Context, pu *ProjectUser) (*ProjectUser, error) {
+	if pu.ProjectID <= 0 {
+		return nil, errors.New("Invalid ProjectUser.ProjectID")
+	}
+
+	pus, err := s.client.Projects.ProjectUsersContext(ctx, pu.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pus {
+		if pus[i].ID == pu.ID {
+			return &pus[i], nil
+		}
+	}
+	return nil, fmt.Errorf("ProjectUser %v not found in project %v", pu.ID, pu.ProjectID)
+}
+
 // MassUpdate mass update project users.
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/project_users.md#mass-update-for-project-users
 func (s *ProjectUsersService) MassUpdate(pids string, pu *ProjectUser) ([]ProjectUser, error) {
+	return s.MassUpdateContext(context.Background(), pids, pu)
+}
+
+// MassUpdateContext is like MassUpdate, but with the provided context.
+func (s *ProjectUsersService) MassUpdateContext(ctx context.Context, pids string, pu *ProjectUser) ([]ProjectUser, error) {
 	u := fmt.Sprintf("project_users/%v", pids)
 
 	puc := &ProjectUserCreate{pu}
-	req, err := s.client.NewRequest("PUT", u, puc)
+	req, err := s.client.NewRequestContext(ctx, "PUT", u, puc)
 	if err != nil {
 		return nil, err
 	}
@@ -142,12 +232,45 @@ func (s *ProjectUsersService) MassUpdate(pids string, pu *ProjectUser) ([]Projec
 	return data.Data, err
 }
 
+// MassUpdateIDs is like MassUpdate, but takes the project user IDs as a
+// list. More than MaxIDsPerRequest ids are updated in several requests. If
+// one of them fails, the project users updated so far are returned with
+// the error.
+func (s *ProjectUsersService) MassUpdateIDs(ids []int, pu *ProjectUser) ([]ProjectUser, error) {
+	return s.MassUpdateIDsContext(context.Background(), ids, pu)
+}
+
+// MassUpdateIDsContext is like MassUpdateIDs, but with the provided context.
+func (s *ProjectUsersService) MassUpdateIDsContext(ctx context.Context, ids []int, pu *ProjectUser) ([]ProjectUser, error) {
+	if pu == nil {
+		return nil, errors.New("ProjectUser cannot be nil")
+	}
+	if err := validateIDs(ids); err != nil {
+		return nil, err
+	}
+
+	var pus []ProjectUser
+	for _, chunk := range chunkIDs(ids) {
+		data, err := s.MassUpdateContext(ctx, joinIDs(chunk), pu)
+		pus = append(pus, data...)
+		if err != nil {
+			return pus, err
+		}
+	}
+	return pus, nil
+}
+
 // Delete a project user.
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/project_users.md#delete-a-project-user
 func (s *ProjectUsersService) Delete(id int) error {
+	return s.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is like Delete, but with the provided context.
+func (s *ProjectUsersService) DeleteContext(ctx context.Context, id int) error {
 	u := fmt.Sprintf("project_users/%v", id)
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, nil)
 	if err != nil {
 		return err
 	}
@@ -160,8 +283,13 @@ func (s *ProjectUsersService) Delete(id int) error {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/project_users.md#delete-multiple-project-users
 func (s *ProjectUsersService) MassDelete(pids string) error {
+	return s.MassDeleteContext(context.Background(), pids)
+}
+
+// MassDeleteContext is like MassDelete, but with the provided context.
+func (s *ProjectUsersService) MassDeleteContext(ctx context.Context, pids string) error {
 	u := fmt.Sprintf("project_users/%v", pids)
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, nil)
 	if err != nil {
 		return err
 	}
@@ -169,3 +297,23 @@ func (s *ProjectUsersService) MassDelete(pids string) error {
 	_, err = s.client.Do(req, nil)
 	return err
 }
+
+// MassDeleteIDs is like MassDelete, but takes the project user IDs as a
+// list. More than MaxIDsPerRequest ids are deleted in several requests.
+func (s *ProjectUsersService) MassDeleteIDs(ids []int) error {
+	return s.MassDeleteIDsContext(context.Background(), ids)
+}
+
+// MassDeleteIDsContext is like MassDeleteIDs, but with the provided context.
+func (s *ProjectUsersService) MassDeleteIDsContext(ctx context.Context, ids []int) error {
+	if err := validateIDs(ids); err != nil {
+		return err
+	}
+
+	for _, chunk := range chunkIDs(ids) {
+		if err := s.MassDeleteContext(ctx, joinIDs(chunk)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestProjectUsersService_Create(t *testing.T) {
@@ -71,6 +72,35 @@ func TestProjectUsersService_MassCreate(t *testing.T) {
 	}
 }
 
+func TestProjectUsersService_MassCreateIDs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	want := &ProjectUserMultipleUserID{ProjectID: 1, UserID: "1,2", Manager: true}
+
+	mux.HandleFunc("/project_users", func(w http.ResponseWriter, r *http.Request) {
+		v := new(ProjectUserMassCreate)
+		json.NewDecoder(r.Body).Decode(v)
+
+		testMethod(t, r, "POST")
+		if !reflect.DeepEqual(v.ProjectUser, want) {
+			t.Errorf("Request body = %+v, want %+v", v.ProjectUser, want)
+		}
+
+		fmt.Fprint(w, `{"data":[{"uid": 1},{"uid": 2}]}`)
+	})
+
+	result, err := client.ProjectUsers.MassCreateIDs([]int{1, 2}, &ProjectUser{ProjectID: 1, Manager: true})
+	if err != nil {
+		t.Errorf("ProjectUsers.MassCreateIDs returned error: %v", err)
+	}
+
+	wantResult := []ProjectUser{{UserID: 1}, {UserID: 2}}
+	if !reflect.DeepEqual(result, wantResult) {
+		t.Errorf("ProjectUsers.MassCreateIDs returned %v, want %v", result, wantResult)
+	}
+}
+
 func TestProjectUsersService_Update(t *testing.T) {
 	setup()
 	defer teardown()
@@ -100,6 +130,36 @@ func TestProjectUsersService_Update(t *testing.T) {
 	}
 }
 
+func TestProjectUsersService_UpdateChecked(t *testing.T) {
+	setup()
+	defer teardown()
+
+	at := time.Date(2013, time.July, 1, 12, 0, 0, 0, time.UTC)
+	input := &ProjectUser{ID: 2, ProjectID: 1, Manager: true, At: &at}
+
+	mux.HandleFunc("/projects/1/project_users", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"id": 1, "pid": 1}, {"id": 2, "pid": 1, "at": "2013-07-01T12:00:00Z"}]`)
+	})
+	mux.HandleFunc("/project_users/2", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		fmt.Fprint(w, `{"data":{"id": 2, "pid": 1, "manager": true}}`)
+	})
+
+	result, err := client.ProjectUsers.UpdateChecked(input, nil)
+	if err != nil {
+		t.Errorf("ProjectUsers.UpdateChecked returned error: %v", err)
+	}
+	want := &ProjectUser{ID: 2, ProjectID: 1, Manager: true}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("ProjectUsers.UpdateChecked returned %+v, want %+v", result, want)
+	}
+
+	if _, err := client.ProjectUsers.UpdateChecked(&ProjectUser{ID: 3, ProjectID: 1, At: &at}, nil); err == nil {
+		t.Errorf("ProjectUsers.UpdateChecked of missing project user returned no error")
+	}
+}
+
 func TestProjectUsersService_MassUpdate(t *testing.T) {
 	setup()
 	defer teardown()
@@ -156,3 +216,59 @@ func TestProjectUsersService_MassDelete(t *testing.T) {
 		t.Errorf("ProjectUsers.MassDelete returned error: %v", err)
 	}
 }
+
+func TestProjectUsersService_MassUpdateIDs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	input := &ProjectUser{Manager: true}
+
+	mux.HandleFunc("/project_users/1,2", func(w http.ResponseWriter, r *http.Request) {
+		v := new(ProjectUserCreate)
+		json.NewDecoder(r.Body).Decode(v)
+
+		testMethod(t, r, "PUT")
+		if !reflect.DeepEqual(v.ProjectUser, input) {
+			t.Errorf("Request body = %+v, want %+v", v.ProjectUser, input)
+		}
+
+		fmt.Fprint(w, `{"data":[{"id": 1, "manager": true},{"id": 2, "manager": true}]}`)
+	})
+
+	result, err := client.ProjectUsers.MassUpdateIDs([]int{1, 2}, input)
+	if err != nil {
+		t.Errorf("ProjectUsers.MassUpdateIDs returned error: %v", err)
+	}
+
+	want := []ProjectUser{{ID: 1, Manager: true}, {ID: 2, Manager: true}}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("ProjectUsers.MassUpdateIDs returned %v, want %v", result, want)
+	}
+}
+
+func TestProjectUsersService_MassUpdateIDs_nil(t *testing.T) {
+	setup()
+	defer teardown()
+
+	if _, err := client.ProjectUsers.MassUpdateIDs([]int{1}, nil); err == nil {
+		t.Errorf("ProjectUsers.MassUpdateIDs returned no error for a nil project user")
+	}
+}
+
+func TestProjectUsersService_MassDeleteIDs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/project_users/1,2", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+	})
+
+	err := client.ProjectUsers.MassDeleteIDs([]int{1, 2})
+	if err != nil {
+		t.Errorf("ProjectUsers.MassDeleteIDs returned error: %v", err)
+	}
+
+	if err := client.ProjectUsers.MassDeleteIDs([]int{0}); err == nil {
+		t.Errorf("ProjectUsers.MassDeleteIDs returned no error for invalid ids")
+	}
+}
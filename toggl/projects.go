@@ -6,6 +6,7 @@
 package toggl
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -21,16 +22,18 @@ type ProjectsService struct {
 
 // Project represents project on a workspace.
 type Project struct {
-	ID          int        `json:"id,omitempty"`
-	Name        string     `json:"name,omitempty"`
-	WorkspaceID int        `json:"wid,omitempty"`
-	ClientID    int        `json:"cid,omitempty"`
-	Active      bool       `json:"active,omitempty"`
-	IsPrivate   bool       `json:"is_private,omitempty"`
-	Template    bool       `json:"template,omitempty"`
-	TemplateID  int        `json:"template_id,omitempty"`
-	Billable    bool       `json:"billable,omitempty"`
-	At          *time.Time `json:"at,omitempty"`
+	ID              int        `json:"id,omitempty"`
+	Name            string     `json:"name,omitempty"`
+	WorkspaceID     int        `json:"wid,omitempty"`
+	ClientID        int        `json:"cid,omitempty"`
+	Active          bool       `json:"active,omitempty"`
+	IsPrivate       bool       `json:"is_private,omitempty"`
+	Template        bool       `json:"template,omitempty"`
+	TemplateID      int        `json:"template_id,omitempty"`
+	Billable        bool       `json:"billable,omitempty"`
+	Rate            float64    `json:"rate,omitempty"` // hourly rate, overrides the client's and workspace's
+	At              *time.Time `json:"at,omitempty"`
+	ServerDeletedAt *time.Time `json:"server_deleted_at,omitempty"` // set once the project is deleted
 }
 
 // ProjectResponse acts as a response wrapper where response returns
@@ -48,9 +51,14 @@ type ProjectCreate struct {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/projects.md#create-project
 func (s *ProjectsService) Create(p *Project) (*Project, error) {
+	return s.CreateContext(context.Background(), p)
+}
+
+// CreateContext is like Create, but with the provided context.
+func (s *ProjectsService) CreateContext(ctx context.Context, p *Project) (*Project, error) {
 	u := "projects"
 	pc := &ProjectCreate{Project: p}
-	req, err := s.client.NewRequest("POST", u, pc)
+	req, err := s.client.NewRequestContext(ctx, "POST", u, pc)
 	if err != nil {
 		return nil, err
 	}
@@ -65,8 +73,13 @@ func (s *ProjectsService) Create(p *Project) (*Project, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/projects.md#get-project-data
 func (s *ProjectsService) Get(id int) (*Project, error) {
+	return s.GetContext(context.Background(), id)
+}
+
+// GetContext is like Get, but with the provided context.
+func (s *ProjectsService) GetContext(ctx context.Context, id int) (*Project, error) {
 	u := fmt.Sprintf("projects/%v", id)
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +94,11 @@ func (s *ProjectsService) Get(id int) (*Project, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/clients.md#update-a-client
 func (s *ProjectsService) Update(p *Project) (*Project, error) {
+	return s.UpdateContext(context.Background(), p)
+}
+
+// UpdateContext is like Update, but with the provided context.
+func (s *ProjectsService) UpdateContext(ctx context.Context, p *Project) (*Project, error) {
 	if p == nil {
 		return nil, errors.New("Project cannot be nil")
 	}
@@ -91,7 +109,7 @@ func (s *ProjectsService) Update(p *Project) (*Project, error) {
 	u := fmt.Sprintf("projects/%v", p.ID)
 
 	pc := &ProjectCreate{p}
-	req, err := s.client.NewRequest("PUT", u, pc)
+	req, err := s.client.NewRequestContext(ctx, "PUT", u, pc)
 	if err != nil {
 		return nil, err
 	}
@@ -102,12 +120,51 @@ func (s *ProjectsService) Update(p *Project) (*Project, error) {
 	return data.Data, err
 }
 
+// Delete a project.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/projects.md#delete-a-project
+func (s *ProjectsService) Delete(id int) error {
+	return s.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is like Delete, but with the provided context.
+func (s *ProjectsService) DeleteContext(ctx context.Context, id int) error {
+	u := fmt.Sprintf("projects/%v", id)
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(req, nil)
+	return err
+}
+
+// UpdateChecked updates a project, unless it changed since p was fetched.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/projects.md#update-project-data
+func (s *ProjectsService) UpdateChecked(p *Project, merge ProjectMergeFunc) (*Project, error) {
+	return s.UpdateCheckedContext(context.Background(), p, merge)
+}
+
+// UpdateCheckedContext is like UpdateChecked, but with the provided context.
+func (s *ProjectsService) UpdateCheckedContext(ctx context.Context, p *Project, merge ProjectMergeFunc) (*Project, error) {
+	return updateChecked("Project", p, merge,
+		func(x *Project) (*int, *time.Time) { return &x.ID, x.At },
+		func(x *Project) (*Project, error) { return s.GetContext(ctx, x.ID) },
+		func(x *Project) (*Project, error) { return s.UpdateContext(ctx, x) })
+}
+
 // ProjectUsers gets project users.
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/projects.md#get-project-users
 func (s *ProjectsService) ProjectUsers(id int) ([]ProjectUser, error) {
+	return s.ProjectUsersContext(context.Background(), id)
+}
+
+// ProjectUsersContext is like ProjectUsers, but with the provided context.
+func (s *ProjectsService) ProjectUsersContext(ctx context.Context, id int) ([]ProjectUser, error) {
 	u := fmt.Sprintf("projects/%v/project_users", id)
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
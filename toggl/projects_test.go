@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestProjectsService_Create(t *testing.T) {
@@ -91,6 +92,111 @@ func TestProjectsService_Update(t *testing.T) {
 	}
 }
 
+func TestProjectsService_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/projects/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+	})
+
+	if err := client.Projects.Delete(1); err != nil {
+		t.Errorf("Projects.Delete returned error: %v", err)
+	}
+}
+
+func TestProjectsService_UpdateChecked(t *testing.T) {
+	setup()
+	defer teardown()
+
+	at := time.Date(2013, time.July, 1, 12, 0, 0, 0, time.UTC)
+	input := &Project{ID: 1, Name: "name", At: &at}
+
+	mux.HandleFunc("/projects/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprint(w, `{"data":{"id": 1, "name": "old", "at": "2013-07-01T12:00:00Z"}}`)
+			return
+		}
+		testMethod(t, r, "PUT")
+		fmt.Fprint(w, `{"data":{"id": 1, "name": "name", "at": "2013-07-01T12:05:00Z"}}`)
+	})
+
+	result, err := client.Projects.UpdateChecked(input, nil)
+	if err != nil {
+		t.Errorf("Projects.UpdateChecked returned error: %v", err)
+	}
+	if result == nil || result.Name != "name" {
+		t.Errorf("Projects.UpdateChecked returned %+v", result)
+	}
+}
+
+func TestProjectsService_UpdateChecked_conflict(t *testing.T) {
+	setup()
+	defer teardown()
+
+	at := time.Date(2013, time.July, 1, 11, 0, 0, 0, time.UTC)
+	theirsAt := time.Date(2013, time.July, 1, 12, 0, 0, 0, time.UTC)
+	input := &Project{ID: 1, Name: "mine", At: &at}
+
+	updated := false
+	mux.HandleFunc("/projects/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprint(w, `{"data":{"id": 1, "name": "theirs", "billable": true, "at": "2013-07-01T12:00:00Z"}}`)
+			return
+		}
+		updated = true
+		v := new(ProjectCreate)
+		json.NewDecoder(r.Body).Decode(v)
+		if want := (&Project{ID: 1, Name: "mine", Billable: true, At: &theirsAt}); !reflect.DeepEqual(v.Project, want) {
+			t.Errorf("Request body = %+v, want %+v", v.Project, want)
+		}
+		fmt.Fprint(w, `{"data":{"id": 1, "name": "mine", "billable": true}}`)
+	})
+
+	_, err := client.Projects.UpdateChecked(input, nil)
+	c, ok := err.(*ConflictError)
+	if !ok || !IsConflict(err) {
+		t.Fatalf("Projects.UpdateChecked returned %v, want *ConflictError", err)
+	}
+	want := &ConflictError{
+		Kind:     "Project",
+		ID:       1,
+		Mine:     input,
+		Theirs:   &Project{ID: 1, Name: "theirs", Billable: true, At: &theirsAt},
+		MineAt:   at,
+		TheirsAt: theirsAt,
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("Projects.UpdateChecked returned %+v, want %+v", c, want)
+	}
+	if updated {
+		t.Errorf("Projects.UpdateChecked updated the project despite the conflict")
+	}
+
+	merge := func(mine, theirs *Project, err *ConflictError) (*Project, error) {
+		merged := *theirs
+		merged.Name = mine.Name
+		return &merged, nil
+	}
+	result, err := client.Projects.UpdateChecked(input, merge)
+	if err != nil {
+		t.Errorf("Projects.UpdateChecked with merge returned error: %v", err)
+	}
+	if !updated || result == nil || result.Name != "mine" || !result.Billable {
+		t.Errorf("Projects.UpdateChecked with merge returned %+v", result)
+	}
+}
+
+func TestProjectsService_UpdateChecked_noAt(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.Projects.UpdateChecked(&Project{ID: 1}, nil)
+	if err == nil {
+		t.Errorf("Projects.UpdateChecked without At returned no error")
+	}
+}
+
 func TestProjectsService_ProjectUsers(t *testing.T) {
 	setup()
 	defer teardown()
@@ -0,0 +1,196 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package rates determines the hourly rate and billable amount of Toggl time
+entries.
+
+Toggl takes the hourly rate of a time entry from the first of its project
+user, project, client and workspace that defines one. A Resolver applies
+the same precedence to data fetched through the toggl services and reports
+which level each rate came from:
+
+	r := rates.NewResolver(workspaces, clients, projects, projectUsers)
+	for _, te := range entries {
+		a := r.Amount(&te, time.Now())
+		fmt.Println(te.ID, a.Value, a.Currency, a.Level)
+	}
+*/
+package rates
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+// Level identifies where an hourly rate is defined.
+type Level int
+
+// Levels in increasing order of precedence.
+const (
+	None Level = iota
+	Workspace
+	Client
+	Project
+	ProjectUser
+)
+
+func (l Level) String() string {
+	switch l {
+	case Workspace:
+		return "workspace"
+	case Client:
+		return "client"
+	case Project:
+		return "project"
+	case ProjectUser:
+		return "project user"
+	}
+	return "none"
+}
+
+// Rate represents the effective hourly rate of a time entry.
+type Rate struct {
+	Hourly   float64
+	Currency string
+
+	// Level the rate is defined at and ID of the workspace, client,
+	// project or project user defining it
+	Level    Level
+	SourceID int
+}
+
+// Amount represents the billable amount of a time entry.
+type Amount struct {
+	Rate
+
+	// Tracked duration of the time entry
+	Duration time.Duration
+
+	// Amount rounded to the minor unit of the currency, see Round
+	Value float64
+}
+
+// Resolver determines effective hourly rates. A rate of 0 counts as not
+// defined, so that the next level applies.
+type Resolver struct {
+	workspaces   map[int]toggl.Workspace
+	clients      map[int]toggl.WorkspaceClient
+	projects     map[int]toggl.Project
+	projectUsers map[[2]int]toggl.ProjectUser // keyed by project and user ID
+}
+
+// NewResolver returns a Resolver for time entries of the given workspaces,
+// clients, projects and project users.
+func NewResolver(workspaces []toggl.Workspace, clients []toggl.WorkspaceClient, projects []toggl.Project, projectUsers []toggl.ProjectUser) *Resolver {
+	r := &Resolver{
+		workspaces:   make(map[int]toggl.Workspace, len(workspaces)),
+		clients:      make(map[int]toggl.WorkspaceClient, len(clients)),
+		projects:     make(map[int]toggl.Project, len(projects)),
+		projectUsers: make(map[[2]int]toggl.ProjectUser, len(projectUsers)),
+	}
+	for _, w := range workspaces {
+		r.workspaces[w.ID] = w
+	}
+	for _, c := range clients {
+		r.clients[c.ID] = c
+	}
+	for _, p := range projects {
+		r.projects[p.ID] = p
+	}
+	for _, pu := range projectUsers {
+		r.projectUsers[[2]int{pu.ProjectID, pu.UserID}] = pu
+	}
+	return r
+}
+
+// Resolve returns the effective hourly rate of te, regardless of whether
+// te is billable. The currency is the client's, or else the workspace's
+// default currency.
+func (r *Resolver) Resolve(te *toggl.TimeEntry) Rate {
+	var rate Rate
+
+	p, hasProject := r.projects[te.ProjectID]
+	c, hasClient := r.clients[p.ClientID]
+	wid := te.WorkspaceID
+	if wid == 0 {
+		wid = p.WorkspaceID
+	}
+	w, hasWorkspace := r.workspaces[wid]
+
+	if pu, ok := r.projectUsers[[2]int{te.ProjectID, te.UserID}]; ok && pu.Rate != 0 {
+		rate = Rate{Hourly: pu.Rate, Level: ProjectUser, SourceID: pu.ID}
+	} else if hasProject && p.Rate != 0 {
+		rate = Rate{Hourly: p.Rate, Level: Project, SourceID: p.ID}
+	} else if hasClient && c.HourlyRate != 0 {
+		rate = Rate{Hourly: c.HourlyRate, Level: Client, SourceID: c.ID}
+	} else if hasWorkspace && w.DefaultHourlyRate != 0 {
+		rate = Rate{Hourly: w.DefaultHourlyRate, Level: Workspace, SourceID: w.ID}
+	}
+
+	if hasClient && c.Currency != "" {
+		rate.Currency = c.Currency
+	} else if hasWorkspace {
+		rate.Currency = w.DefaultCurrency
+	}
+	return rate
+}
+
+// Amount returns the billable amount of te. Running entries are measured
+// up to now. The value of non-billable entries is 0.
+func (r *Resolver) Amount(te *toggl.TimeEntry, now time.Time) Amount {
+	a := Amount{
+		Rate:     r.Resolve(te),
+		Duration: te.Elapsed(now),
+	}
+	if te.Billable {
+		a.Value = Cost(a.Hourly, a.Duration, a.Currency)
+	}
+	return a
+}
+
+// Cost returns the cost of d at the given hourly rate, rounded to the
+// minor unit of currency.
+func Cost(hourly float64, d time.Duration, currency string) float64 {
+	scale := math.Pow10(MinorUnits(currency))
+	minor := hourly * scale * d.Hours()
+	return roundHalfAway(minor) / scale
+}
+
+// Round rounds v to the minor unit of currency, half away from zero.
+func Round(v float64, currency string) float64 {
+	scale := math.Pow10(MinorUnits(currency))
+	return roundHalfAway(v*scale) / scale
+}
+
+// roundHalfAway rounds v to an integer, tolerating the representation
+// error of decimal amounts such as 1.005 * 100.
+func roundHalfAway(v float64) float64 {
+	const epsilon = 1e-9
+	if v < 0 {
+		return -math.Floor(-v + 0.5 + epsilon)
+	}
+	return math.Floor(v + 0.5 + epsilon)
+}
+
+// minorUnits lists ISO 4217 currencies without two decimal places.
+var minorUnits = map[string]int{
+	"BHD": 3, "BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3, "ISK": 0,
+	"JOD": 3, "JPY": 0, "KMF": 0, "KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3,
+	"PYG": 0, "RWF": 0, "TND": 3, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0,
+	"XOF": 0, "XPF": 0,
+}
+
+// MinorUnits returns the number of decimal places of currency, an ISO 4217
+// code. Unknown currencies have two.
+func MinorUnits(currency string) int {
+	if n, ok := minorUnits[strings.ToUpper(currency)]; ok {
+		return n
+	}
+	return 2
+}
@@ -0,0 +1,114 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+var resolver = NewResolver(
+	[]toggl.Workspace{{ID: 1, DefaultHourlyRate: 50, DefaultCurrency: "USD"}},
+	[]toggl.WorkspaceClient{
+		{ID: 10, WorkspaceID: 1, HourlyRate: 80, Currency: "EUR"},
+		{ID: 11, WorkspaceID: 1},
+	},
+	[]toggl.Project{
+		{ID: 100, WorkspaceID: 1, ClientID: 10, Rate: 90},
+		{ID: 101, WorkspaceID: 1, ClientID: 10},
+		{ID: 102, WorkspaceID: 1, ClientID: 11},
+	},
+	[]toggl.ProjectUser{
+		{ID: 1000, ProjectID: 100, UserID: 7, Rate: 120},
+		{ID: 1001, ProjectID: 101, UserID: 8},
+	},
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	tests := []struct {
+		te   toggl.TimeEntry
+		want Rate
+	}{
+		{toggl.TimeEntry{ProjectID: 100, UserID: 7}, Rate{120, "EUR", ProjectUser, 1000}},
+		{toggl.TimeEntry{ProjectID: 100, UserID: 8}, Rate{90, "EUR", Project, 100}},
+		{toggl.TimeEntry{ProjectID: 101, UserID: 8}, Rate{80, "EUR", Client, 10}},
+		{toggl.TimeEntry{ProjectID: 102, UserID: 7}, Rate{50, "USD", Workspace, 1}},
+		{toggl.TimeEntry{WorkspaceID: 1}, Rate{50, "USD", Workspace, 1}},
+		{toggl.TimeEntry{WorkspaceID: 2}, Rate{}},
+	}
+
+	for _, tt := range tests {
+		if got := resolver.Resolve(&tt.te); got != tt.want {
+			t.Errorf("Resolve(%+v) = %+v, want %+v", tt.te, got, tt.want)
+		}
+	}
+}
+
+func TestResolver_Amount(t *testing.T) {
+	te := &toggl.TimeEntry{ProjectID: 100, UserID: 7, Billable: true, Duration: 5400}
+
+	a := resolver.Amount(te, time.Now())
+	if a.Value != 180 || a.Duration != 90*time.Minute || a.Level != ProjectUser {
+		t.Errorf("Amount() = %+v, want 180 EUR for 90m at project user level", a)
+	}
+
+	te.Billable = false
+	if a := resolver.Amount(te, time.Now()); a.Value != 0 || a.Hourly != 120 {
+		t.Errorf("Amount() of non-billable entry = %+v, want value 0 at rate 120", a)
+	}
+}
+
+func TestCost(t *testing.T) {
+	tests := []struct {
+		hourly   float64
+		d        time.Duration
+		currency string
+		want     float64
+	}{
+		{100, 20 * time.Minute, "EUR", 33.33},
+		{100, 40 * time.Minute, "EUR", 66.67},
+		{1000, 20 * time.Minute, "JPY", 333},
+		{10, 37 * time.Second, "KWD", 0.103},
+		{0, time.Hour, "EUR", 0},
+	}
+
+	for _, tt := range tests {
+		if got := Cost(tt.hourly, tt.d, tt.currency); got != tt.want {
+			t.Errorf("Cost(%v, %v, %v) = %v, want %v", tt.hourly, tt.d, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		v        float64
+		currency string
+		want     float64
+	}{
+		{1.005, "USD", 1.01},
+		{-1.005, "usd", -1.01},
+		{2.5, "JPY", 3},
+		{1.0005, "BHD", 1.001},
+		{1.004, "XYZ", 1},
+	}
+
+	for _, tt := range tests {
+		if got := Round(tt.v, tt.currency); got != tt.want {
+			t.Errorf("Round(%v, %v) = %v, want %v", tt.v, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestLevel_String(t *testing.T) {
+	if got := ProjectUser.String(); got != "project user" {
+		t.Errorf("ProjectUser.String() = %q, want %q", got, "project user")
+	}
+	if got := None.String(); got != "none" {
+		t.Errorf("None.String() = %q, want %q", got, "none")
+	}
+}
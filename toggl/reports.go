@@ -0,0 +1,505 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ReportsService handles communication with the Toggl Reports API v2.
+// Its requests are sent to the Client's ReportsURL.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/reports.md
+type ReportsService struct {
+	client *Client
+}
+
+// Values for ReportOptions.Grouping and ReportOptions.Subgrouping. Summary
+// reports group by projects, clients or users and subgroup by any of
+// these; weekly reports group by projects or users.
+const (
+	GroupByProjects    = "projects"
+	GroupByClients     = "clients"
+	GroupByUsers       = "users"
+	GroupByTasks       = "tasks"
+	GroupByTimeEntries = "time_entries"
+)
+
+// Values for ReportOptions.Billable.
+const (
+	BillableYes  = "yes"
+	BillableNo   = "no"
+	BillableBoth = "both"
+)
+
+// ReportFormat is a file format reports can be exported to.
+type ReportFormat string
+
+// Report export formats.
+const (
+	ReportPDF ReportFormat = "pdf"
+	ReportCSV ReportFormat = "csv"
+)
+
+// ReportOptions represents the parameters of a report request. Zero values
+// are left out of the request so that the API defaults apply.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/reports.md#request-parameters
+type ReportOptions struct {
+	// Workspace to report on, required
+	WorkspaceID int
+
+	// First and last day of the report, only the dates are used
+	Since time.Time
+	Until time.Time
+
+	// BillableYes, BillableNo or BillableBoth
+	Billable string
+
+	// Filters by IDs; an ID of 0 selects entries without client, project,
+	// user, tag or task respectively
+	ClientIDs  []int
+	ProjectIDs []int
+	UserIDs    []int
+	TagIDs     []int
+	TaskIDs    []int
+
+	// Filter by time entry description
+	Description        string
+	WithoutDescription bool
+
+	// Sort order, e.g. "date", "duration" or "title"
+	OrderField string
+	OrderDesc  bool
+
+	// Whether rates are shown separately and durations are rounded as set
+	// in the workspace
+	DistinctRates bool
+	Rounding      bool
+
+	// "decimal" or "minutes"
+	DisplayHours string
+
+	// Grouping of summary and weekly reports, see GroupByProjects
+	Grouping    string
+	Subgrouping string
+
+	// Page of a detailed report, starting at 1
+	Page int
+}
+
+// values encodes the options as query parameters. The user_agent
+// parameter required by the Reports API is set to userAgent.
+func (o *ReportOptions) values(userAgent string) url.Values {
+	params := url.Values{}
+	params.Set("user_agent", userAgent)
+	params.Set("workspace_id", strconv.Itoa(o.WorkspaceID))
+
+	if !o.Since.IsZero() {
+		params.Set("since", o.Since.Format("2006-01-02"))
+	}
+	if !o.Until.IsZero() {
+		params.Set("until", o.Until.Format("2006-01-02"))
+	}
+
+	setString := func(key, value string) {
+		if value != "" {
+			params.Set(key, value)
+		}
+	}
+	setIDs := func(key string, ids []int) {
+		if len(ids) > 0 {
+			params.Set(key, joinIDs(ids))
+		}
+	}
+	setFlag := func(key string, on bool) {
+		if on {
+			params.Set(key, "on")
+		}
+	}
+
+	setString("billable", o.Billable)
+	setIDs("client_ids", o.ClientIDs)
+	setIDs("project_ids", o.ProjectIDs)
+	setIDs("user_ids", o.UserIDs)
+	setIDs("tag_ids", o.TagIDs)
+	setIDs("task_ids", o.TaskIDs)
+	setString("description", o.Description)
+	if o.WithoutDescription {
+		params.Set("without_description", "true")
+	}
+	setString("order_field", o.OrderField)
+	setFlag("order_desc", o.OrderDesc)
+	setFlag("distinct_rates", o.DistinctRates)
+	setFlag("rounding", o.Rounding)
+	setString("display_hours", o.DisplayHours)
+	setString("grouping", o.Grouping)
+	setString("subgrouping", o.Subgrouping)
+	if o.Page > 0 {
+		params.Set("page", strconv.Itoa(o.Page))
+	}
+
+	return params
+}
+
+// ReportCurrency represents an amount earned in a currency.
+type ReportCurrency struct {
+	Currency string  `json:"currency,omitempty"`
+	Amount   float64 `json:"amount,omitempty"`
+}
+
+// ReportTitle represents the title of a report group or item. Which fields
+// are set depends on the grouping.
+type ReportTitle struct {
+	Project   string `json:"project,omitempty"`
+	Client    string `json:"client,omitempty"`
+	User      string `json:"user,omitempty"`
+	Task      string `json:"task,omitempty"`
+	TimeEntry string `json:"time_entry,omitempty"`
+	Color     string `json:"color,omitempty"`
+	HexColor  string `json:"hex_color,omitempty"`
+}
+
+// DetailedReport represents one page of a detailed report. Durations are
+// in milliseconds.
+type DetailedReport struct {
+	TotalGrand      int64                `json:"total_grand,omitempty"`
+	TotalBillable   int64                `json:"total_billable,omitempty"`
+	TotalCurrencies []ReportCurrency     `json:"total_currencies,omitempty"`
+	TotalCount      int                  `json:"total_count,omitempty"`
+	PerPage         int                  `json:"per_page,omitempty"`
+	Data            []DetailedReportItem `json:"data,omitempty"`
+}
+
+// DetailedReportItem represents a time entry in a detailed report.
+type DetailedReportItem struct {
+	ID              int        `json:"id,omitempty"`
+	ProjectID       int        `json:"pid,omitempty"`
+	TaskID          int        `json:"tid,omitempty"`
+	UserID          int        `json:"uid,omitempty"`
+	Description     string     `json:"description,omitempty"`
+	Start           *time.Time `json:"start,omitempty"`
+	End             *time.Time `json:"end,omitempty"`
+	Updated         *time.Time `json:"updated,omitempty"`
+	Duration        int64      `json:"dur,omitempty"` // in milliseconds
+	User            string     `json:"user,omitempty"`
+	UseStop         bool       `json:"use_stop,omitempty"`
+	Client          string     `json:"client,omitempty"`
+	Project         string     `json:"project,omitempty"`
+	ProjectColor    string     `json:"project_color,omitempty"`
+	ProjectHexColor string     `json:"project_hex_color,omitempty"`
+	Task            string     `json:"task,omitempty"`
+	Billable        float64    `json:"billable,omitempty"` // billed amount
+	IsBillable      bool       `json:"is_billable,omitempty"`
+	Currency        string     `json:"cur,omitempty"`
+	Tags            []string   `json:"tags,omitempty"`
+}
+
+// SummaryReport represents a summary report. Durations are in milliseconds.
+type SummaryReport struct {
+	TotalGrand      int64                `json:"total_grand,omitempty"`
+	TotalBillable   int64                `json:"total_billable,omitempty"`
+	TotalCurrencies []ReportCurrency     `json:"total_currencies,omitempty"`
+	Data            []SummaryReportGroup `json:"data,omitempty"`
+}
+
+// SummaryReportGroup represents a group of a summary report, such as a
+// project, with its subgroups as items.
+type SummaryReportGroup struct {
+	ID              int                 `json:"id,omitempty"`
+	Title           ReportTitle         `json:"title"`
+	Time            int64               `json:"time,omitempty"`
+	TotalCurrencies []ReportCurrency    `json:"total_currencies,omitempty"`
+	Items           []SummaryReportItem `json:"items,omitempty"`
+}
+
+// SummaryReportItem represents a subgroup of a summary report group.
+type SummaryReportItem struct {
+	Title    ReportTitle `json:"title"`
+	Time     int64       `json:"time,omitempty"`
+	Currency string      `json:"cur,omitempty"`
+	Sum      float64     `json:"sum,omitempty"`
+	Rate     float64     `json:"rate,omitempty"`
+}
+
+// WeeklyReport represents a weekly report. Durations are in milliseconds
+// and totals hold one value per weekday followed by the week's total.
+type WeeklyReport struct {
+	TotalGrand      int64               `json:"total_grand,omitempty"`
+	TotalBillable   int64               `json:"total_billable,omitempty"`
+	TotalCurrencies []ReportCurrency    `json:"total_currencies,omitempty"`
+	WeekTotals      []int64             `json:"week_totals,omitempty"`
+	Data            []WeeklyReportGroup `json:"data,omitempty"`
+}
+
+// WeeklyReportGroup represents a project or user row of a weekly report.
+type WeeklyReportGroup struct {
+	ProjectID int                  `json:"pid,omitempty"`
+	UserID    int                  `json:"uid,omitempty"`
+	Title     ReportTitle          `json:"title"`
+	Totals    []int64              `json:"totals,omitempty"`
+	Details   []WeeklyReportDetail `json:"details,omitempty"`
+}
+
+// WeeklyReportDetail represents a user or project row within a weekly
+// report group.
+type WeeklyReportDetail struct {
+	ProjectID int         `json:"pid,omitempty"`
+	UserID    int         `json:"uid,omitempty"`
+	Title     ReportTitle `json:"title"`
+	Totals    []int64     `json:"totals,omitempty"`
+}
+
+// Detailed returns a page of the detailed report, selected by opts.Page.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/reports/detailed.md
+func (s *ReportsService) Detailed(opts *ReportOptions) (*DetailedReport, error) {
+	return s.DetailedContext(context.Background(), opts)
+}
+
+// DetailedContext is like Detailed, but with the provided context.
+func (s *ReportsService) DetailedContext(ctx context.Context, opts *ReportOptions) (*DetailedReport, error) {
+	req, err := s.newRequest(ctx, "details", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	data := new(DetailedReport)
+	if _, err = s.client.Do(req, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// DetailedIterator walks all pages of a detailed report, fetching each
+// page when the previous one is exhausted:
+//
+//	it := c.Reports.DetailedIter(opts)
+//	for it.Next() {
+//		item := it.Entry()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Stopping before Next returns false leaves the remaining pages unfetched.
+type DetailedIterator struct {
+	ctx   context.Context
+	s     *ReportsService
+	opts  ReportOptions
+	page  *DetailedReport
+	items []DetailedReportItem
+	item  DetailedReportItem
+	done  bool
+	err   error
+}
+
+// DetailedIter returns an iterator over all entries of the detailed
+// report, starting at page opts.Page or the first page.
+func (s *ReportsService) DetailedIter(opts *ReportOptions) *DetailedIterator {
+	return s.DetailedIterContext(context.Background(), opts)
+}
+
+// DetailedIterContext is like DetailedIter, but with the provided context.
+func (s *ReportsService) DetailedIterContext(ctx context.Context, opts *ReportOptions) *DetailedIterator {
+	it := &DetailedIterator{ctx: ctx, s: s}
+	if opts == nil {
+		it.err = errors.New("ReportOptions cannot be nil")
+		return it
+	}
+	it.opts = *opts
+	if it.opts.Page < 1 {
+		it.opts.Page = 1
+	}
+	return it
+}
+
+// Next advances to the next entry, which is then available through Entry.
+// It returns false when there are no more entries or an error occurred.
+func (it *DetailedIterator) Next() bool {
+	for len(it.items) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.fetch()
+	}
+	it.item, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+func (it *DetailedIterator) fetch() {
+	if it.page != nil {
+		it.opts.Page++
+	}
+
+	page, err := it.s.DetailedContext(it.ctx, &it.opts)
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	it.page = page
+	it.items = page.Data
+	if len(page.Data) == 0 || len(page.Data) < page.PerPage ||
+		(it.opts.Page-1)*page.PerPage+len(page.Data) >= page.TotalCount {
+		it.done = true
+	}
+}
+
+// Entry returns the current entry.
+func (it *DetailedIterator) Entry() DetailedReportItem {
+	return it.item
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *DetailedIterator) Err() error {
+	return it.err
+}
+
+// TotalCount returns the number of entries in the report as reported by
+// the API. It is 0 until the first call to Next.
+func (it *DetailedIterator) TotalCount() int {
+	if it.page == nil {
+		return 0
+	}
+	return it.page.TotalCount
+}
+
+// Report returns the most recently fetched page, which carries the
+// report's totals, or nil until the first call to Next.
+func (it *DetailedIterator) Report() *DetailedReport {
+	return it.page
+}
+
+// Summary returns the summary report.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/reports/summary.md
+func (s *ReportsService) Summary(opts *ReportOptions) (*SummaryReport, error) {
+	return s.SummaryContext(context.Background(), opts)
+}
+
+// SummaryContext is like Summary, but with the provided context.
+func (s *ReportsService) SummaryContext(ctx context.Context, opts *ReportOptions) (*SummaryReport, error) {
+	req, err := s.newRequest(ctx, "summary", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	data := new(SummaryReport)
+	if _, err = s.client.Do(req, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Weekly returns the weekly report of the week starting at opts.Since.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/reports/weekly.md
+func (s *ReportsService) Weekly(opts *ReportOptions) (*WeeklyReport, error) {
+	return s.WeeklyContext(context.Background(), opts)
+}
+
+// WeeklyContext is like Weekly, but with the provided context.
+func (s *ReportsService) WeeklyContext(ctx context.Context, opts *ReportOptions) (*WeeklyReport, error) {
+	req, err := s.newRequest(ctx, "weekly", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	data := new(WeeklyReport)
+	if _, err = s.client.Do(req, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// DetailedExport returns the detailed report as a file in the given
+// format. The file is streamed from the API as it is read; the caller must
+// close it.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/reports.md#response
+func (s *ReportsService) DetailedExport(opts *ReportOptions, format ReportFormat) (io.ReadCloser, error) {
+	return s.DetailedExportContext(context.Background(), opts, format)
+}
+
+// DetailedExportContext is like DetailedExport, but with the provided context.
+func (s *ReportsService) DetailedExportContext(ctx context.Context, opts *ReportOptions, format ReportFormat) (io.ReadCloser, error) {
+	return s.export(ctx, "details", opts, format)
+}
+
+// SummaryExport returns the summary report as a file in the given format.
+// The file is streamed from the API as it is read; the caller must close
+// it.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/reports.md#response
+func (s *ReportsService) SummaryExport(opts *ReportOptions, format ReportFormat) (io.ReadCloser, error) {
+	return s.SummaryExportContext(context.Background(), opts, format)
+}
+
+// SummaryExportContext is like SummaryExport, but with the provided context.
+func (s *ReportsService) SummaryExportContext(ctx context.Context, opts *ReportOptions, format ReportFormat) (io.ReadCloser, error) {
+	return s.export(ctx, "summary", opts, format)
+}
+
+// WeeklyExport returns the weekly report as a file in the given format.
+// The file is streamed from the API as it is read; the caller must close
+// it.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/reports.md#response
+func (s *ReportsService) WeeklyExport(opts *ReportOptions, format ReportFormat) (io.ReadCloser, error) {
+	return s.WeeklyExportContext(context.Background(), opts, format)
+}
+
+// WeeklyExportContext is like WeeklyExport, but with the provided context.
+func (s *ReportsService) WeeklyExportContext(ctx context.Context, opts *ReportOptions, format ReportFormat) (io.ReadCloser, error) {
+	return s.export(ctx, "weekly", opts, format)
+}
+
+// export requests the report at path in the given format and returns the
+// unread response body.
+func (s *ReportsService) export(ctx context.Context, path string, opts *ReportOptions, format ReportFormat) (io.ReadCloser, error) {
+	switch format {
+	case ReportPDF, ReportCSV:
+	default:
+		return nil, fmt.Errorf("Invalid report format %q", format)
+	}
+
+	req, err := s.newRequest(ctx, path+"."+string(format), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.DoRaw(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// newRequest creates a GET request for the report at path, relative to
+// the Client's ReportsURL.
+func (s *ReportsService) newRequest(ctx context.Context, path string, opts *ReportOptions) (*http.Request, error) {
+	if opts == nil {
+		return nil, errors.New("ReportOptions cannot be nil")
+	}
+	if opts.WorkspaceID <= 0 {
+		return nil, errors.New("Invalid ReportOptions.WorkspaceID")
+	}
+
+	u := s.client.ReportsURL.ResolveReference(&url.URL{
+		Path:     path,
+		RawQuery: opts.values(s.client.UserAgent).Encode(),
+	})
+	return s.client.NewRequestContext(ctx, "GET", u.String(), nil)
+}
@@ -0,0 +1,393 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggl
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReportOptions_values(t *testing.T) {
+	opts := &ReportOptions{
+		WorkspaceID:        1,
+		Since:              time.Date(2013, time.March, 1, 0, 0, 0, 0, time.UTC),
+		Until:              time.Date(2013, time.March, 31, 0, 0, 0, 0, time.UTC),
+		Billable:           BillableYes,
+		ClientIDs:          []int{1, 2},
+		ProjectIDs:         []int{3},
+		UserIDs:            []int{4},
+		TagIDs:             []int{0},
+		TaskIDs:            []int{5},
+		Description:        "desc",
+		WithoutDescription: true,
+		OrderField:         "date",
+		OrderDesc:          true,
+		DistinctRates:      true,
+		Rounding:           true,
+		DisplayHours:       "decimal",
+		Grouping:           GroupByClients,
+		Subgrouping:        GroupByProjects,
+		Page:               2,
+	}
+
+	got := opts.values("ua")
+	want := map[string]string{
+		"user_agent":          "ua",
+		"workspace_id":        "1",
+		"since":               "2013-03-01",
+		"until":               "2013-03-31",
+		"billable":            "yes",
+		"client_ids":          "1,2",
+		"project_ids":         "3",
+		"user_ids":            "4",
+		"tag_ids":             "0",
+		"task_ids":            "5",
+		"description":         "desc",
+		"without_description": "true",
+		"order_field":         "date",
+		"order_desc":          "on",
+		"distinct_rates":      "on",
+		"rounding":            "on",
+		"display_hours":       "decimal",
+		"grouping":            "clients",
+		"subgrouping":         "projects",
+		"page":                "2",
+	}
+	if len(got) != len(want) {
+		t.Errorf("values() returned %d parameters, want %d", len(got), len(want))
+	}
+	for k, v := range want {
+		if got.Get(k) != v {
+			t.Errorf("values() parameter %v = %v, want %v", k, got.Get(k), v)
+		}
+	}
+
+	minimal := (&ReportOptions{WorkspaceID: 1}).values("ua")
+	if len(minimal) != 2 {
+		t.Errorf("values() of minimal options = %v, want only user_agent and workspace_id", minimal)
+	}
+}
+
+func TestReportsService_Detailed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/reports/api/v2/details", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{
+			"user_agent":   UserAgent,
+			"workspace_id": "1",
+			"page":         "2",
+		})
+		fmt.Fprint(w, `{
+			"total_grand": 3600000,
+			"total_billable": null,
+			"total_count": 51,
+			"per_page": 50,
+			"total_currencies": [{"currency": "EUR", "amount": 10}],
+			"data": [{
+				"id": 1, "pid": 2, "tid": null, "uid": 3,
+				"description": "desc",
+				"start": "2013-03-11T11:36:00Z",
+				"dur": 3600000,
+				"project": "Project",
+				"tags": ["billed"]
+			}]
+		}`)
+	})
+
+	result, err := client.Reports.Detailed(&ReportOptions{WorkspaceID: 1, Page: 2})
+	if err != nil {
+		t.Errorf("Reports.Detailed returned error: %v", err)
+	}
+
+	start := time.Date(2013, time.March, 11, 11, 36, 0, 0, time.UTC)
+	want := &DetailedReport{
+		TotalGrand:      3600000,
+		TotalCount:      51,
+		PerPage:         50,
+		TotalCurrencies: []ReportCurrency{{Currency: "EUR", Amount: 10}},
+		Data: []DetailedReportItem{{
+			ID:          1,
+			ProjectID:   2,
+			UserID:      3,
+			Description: "desc",
+			Start:       &start,
+			Duration:    3600000,
+			Project:     "Project",
+			Tags:        []string{"billed"},
+		}},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Reports.Detailed returned %+v, want %+v", result, want)
+	}
+}
+
+func TestReportsService_Summary(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/reports/api/v2/summary", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{
+			"workspace_id": "1",
+			"grouping":     "projects",
+			"subgrouping":  "time_entries",
+		})
+		fmt.Fprint(w, `{
+			"total_grand": 7200000,
+			"data": [{
+				"id": 2,
+				"title": {"project": "Project", "client": "Client"},
+				"time": 7200000,
+				"items": [{"title": {"time_entry": "desc"}, "time": 7200000, "cur": "EUR", "sum": 20, "rate": 10}]
+			}]
+		}`)
+	})
+
+	result, err := client.Reports.Summary(&ReportOptions{
+		WorkspaceID: 1,
+		Grouping:    GroupByProjects,
+		Subgrouping: GroupByTimeEntries,
+	})
+	if err != nil {
+		t.Errorf("Reports.Summary returned error: %v", err)
+	}
+
+	want := &SummaryReport{
+		TotalGrand: 7200000,
+		Data: []SummaryReportGroup{{
+			ID:    2,
+			Title: ReportTitle{Project: "Project", Client: "Client"},
+			Time:  7200000,
+			Items: []SummaryReportItem{{
+				Title:    ReportTitle{TimeEntry: "desc"},
+				Time:     7200000,
+				Currency: "EUR",
+				Sum:      20,
+				Rate:     10,
+			}},
+		}},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Reports.Summary returned %+v, want %+v", result, want)
+	}
+}
+
+func TestReportsService_Weekly(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/reports/api/v2/weekly", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{
+			"workspace_id": "1",
+			"since":        "2013-03-11",
+		})
+		fmt.Fprint(w, `{
+			"total_grand": 3600000,
+			"week_totals": [3600000, null, null, null, null, null, null, 3600000],
+			"data": [{
+				"pid": 2,
+				"title": {"project": "Project"},
+				"totals": [3600000, null, null, null, null, null, null, 3600000],
+				"details": [{"uid": 3, "title": {"user": "User"}, "totals": [3600000, null, null, null, null, null, null, 3600000]}]
+			}]
+		}`)
+	})
+
+	result, err := client.Reports.Weekly(&ReportOptions{
+		WorkspaceID: 1,
+		Since:       time.Date(2013, time.March, 11, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Errorf("Reports.Weekly returned error: %v", err)
+	}
+
+	totals := []int64{3600000, 0, 0, 0, 0, 0, 0, 3600000}
+	want := &WeeklyReport{
+		TotalGrand: 3600000,
+		WeekTotals: totals,
+		Data: []WeeklyReportGroup{{
+			ProjectID: 2,
+			Title:     ReportTitle{Project: "Project"},
+			Totals:    totals,
+			Details: []WeeklyReportDetail{{
+				UserID: 3,
+				Title:  ReportTitle{User: "User"},
+				Totals: totals,
+			}},
+		}},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Reports.Weekly returned %+v, want %+v", result, want)
+	}
+}
+
+func TestReportsService_invalidOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	if _, err := client.Reports.Summary(nil); err == nil {
+		t.Errorf("Reports.Summary returned no error for nil options")
+	}
+	if _, err := client.Reports.Summary(&ReportOptions{}); err == nil {
+		t.Errorf("Reports.Summary returned no error without workspace")
+	}
+}
+
+func TestReportsService_Export(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for _, path := range []string{"details.pdf", "summary.csv", "weekly.pdf"} {
+		path := path
+		mux.HandleFunc("/reports/api/v2/"+path, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			testFormValues(t, r, values{"workspace_id": "1"})
+			fmt.Fprint(w, path)
+		})
+	}
+
+	opts := &ReportOptions{WorkspaceID: 1}
+	tests := []struct {
+		export func(*ReportOptions, ReportFormat) (io.ReadCloser, error)
+		format ReportFormat
+		want   string
+	}{
+		{client.Reports.DetailedExport, ReportPDF, "details.pdf"},
+		{client.Reports.SummaryExport, ReportCSV, "summary.csv"},
+		{client.Reports.WeeklyExport, ReportPDF, "weekly.pdf"},
+	}
+
+	for _, tt := range tests {
+		rc, err := tt.export(opts, tt.format)
+		if err != nil {
+			t.Errorf("export of %v returned error: %v", tt.want, err)
+			continue
+		}
+		b, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil || string(b) != tt.want {
+			t.Errorf("export returned %q, %v, want %q", b, err, tt.want)
+		}
+	}
+}
+
+func TestReportsService_Export_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/reports/api/v2/details.csv", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	})
+
+	_, err := client.Reports.DetailedExport(&ReportOptions{WorkspaceID: 1}, ReportCSV)
+	if !IsUnauthorized(err) {
+		t.Errorf("Reports.DetailedExport returned error %v, want 403 ErrorResponse", err)
+	}
+
+	if _, err := client.Reports.DetailedExport(&ReportOptions{WorkspaceID: 1}, "xls"); err == nil {
+		t.Errorf("Reports.DetailedExport returned no error for invalid format")
+	}
+}
+
+func TestReportsService_DetailedIter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var pages []string
+	mux.HandleFunc("/reports/api/v2/details", func(w http.ResponseWriter, r *http.Request) {
+		page := r.FormValue("page")
+		pages = append(pages, page)
+		switch page {
+		case "1":
+			fmt.Fprint(w, `{"total_count": 5, "per_page": 2, "data": [{"id": 1}, {"id": 2}]}`)
+		case "2":
+			fmt.Fprint(w, `{"total_count": 5, "per_page": 2, "data": [{"id": 3}, {"id": 4}]}`)
+		case "3":
+			fmt.Fprint(w, `{"total_count": 5, "per_page": 2, "data": [{"id": 5}]}`)
+		default:
+			t.Errorf("Requested unexpected page %v", page)
+		}
+	})
+
+	opts := &ReportOptions{WorkspaceID: 1}
+	it := client.Reports.DetailedIter(opts)
+	if it.TotalCount() != 0 {
+		t.Errorf("TotalCount() before Next = %v, want 0", it.TotalCount())
+	}
+
+	var ids []int
+	for it.Next() {
+		ids = append(ids, it.Entry().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("DetailedIterator returned error: %v", err)
+	}
+
+	if want := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("DetailedIterator returned entries %v, want %v", ids, want)
+	}
+	if want := []string{"1", "2", "3"}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("DetailedIterator requested pages %v, want %v", pages, want)
+	}
+	if it.TotalCount() != 5 {
+		t.Errorf("TotalCount() = %v, want 5", it.TotalCount())
+	}
+	if opts.Page != 0 {
+		t.Errorf("DetailedIterator modified the caller's options")
+	}
+}
+
+func TestReportsService_DetailedIter_earlyStop(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requests := 0
+	mux.HandleFunc("/reports/api/v2/details", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"total_count": 4, "per_page": 2, "data": [{"id": 1}, {"id": 2}]}`)
+	})
+
+	it := client.Reports.DetailedIter(&ReportOptions{WorkspaceID: 1})
+	for it.Next() {
+		break
+	}
+	if requests != 1 {
+		t.Errorf("DetailedIterator sent %d requests, want 1", requests)
+	}
+}
+
+func TestReportsService_DetailedIter_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/reports/api/v2/details", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("page") == "2" {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `{"total_count": 4, "per_page": 2, "data": [{"id": 1}, {"id": 2}]}`)
+	})
+
+	it := client.Reports.DetailedIter(&ReportOptions{WorkspaceID: 1})
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("DetailedIterator returned %d entries, want 2", n)
+	}
+	if statusCode(it.Err()) != http.StatusInternalServerError {
+		t.Errorf("DetailedIterator returned error %v, want 500 ErrorResponse", it.Err())
+	}
+}
@@ -0,0 +1,401 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package resolve maps names of clients, projects, tasks and tags to their
+Toggl IDs.
+
+Projects and tasks are named by paths of the names of their client,
+project and task, separated by "/":
+
+	r := resolve.New(client)
+	pid, err := r.ProjectID(wid, "Acme / Website redesign")
+	tid, err := r.TaskID(wid, "Acme / Website redesign / QA")
+
+The client may be left out when it does not make the path ambiguous, as
+in "Website redesign / QA". Names are matched regardless of case and of
+the spacing around "/".
+
+A name matching several objects fails with an *AmbiguousError listing
+them, and a name matching none with a *NotFoundError suggesting similar
+names.
+
+The names of a workspace are listed on its first lookup and kept until
+Invalidate is called, or MaxAge has passed.
+*/
+package resolve
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+// Kinds of resolved objects.
+const (
+	KindClient  = "client"
+	KindProject = "project"
+	KindTask    = "task"
+	KindTag     = "tag"
+)
+
+// MaxSuggestions is the largest number of names suggested by a
+// NotFoundError.
+const MaxSuggestions = 3
+
+// NotFoundError is returned when no object has the name looked up.
+type NotFoundError struct {
+	Kind string
+	Name string
+
+	// Similar names, closest first
+	Suggestions []string
+}
+
+func (e *NotFoundError) Error() string {
+	msg := fmt.Sprintf("no %s named %q", e.Kind, e.Name)
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf("; did you mean %s?", quoteAll(e.Suggestions, " or "))
+	}
+	return msg
+}
+
+// AmbiguousError is returned when several objects have the name looked
+// up.
+type AmbiguousError struct {
+	Kind string
+	Name string
+
+	// Complete paths of the matching objects
+	Matches []string
+}
+
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("%s %q is ambiguous: %s", e.Kind, e.Name, quoteAll(e.Matches, ", "))
+}
+
+func quoteAll(list []string, sep string) string {
+	quoted := make([]string, len(list))
+	for i, s := range list {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return strings.Join(quoted, sep)
+}
+
+// Resolver looks up objects by name. It is safe for concurrent use.
+type Resolver struct {
+	// How long the names of a workspace are kept. They are kept until
+	// Invalidate is called if MaxAge is 0.
+	MaxAge time.Duration
+
+	client *toggl.Client
+	now    func() time.Time
+
+	mu       sync.Mutex
+	indexes  map[int]*index
+	listings map[int]*listing
+}
+
+// listing is an index being listed, which concurrent lookups wait for.
+type listing struct {
+	done chan struct{}
+	idx  *index
+	err  error
+}
+
+// New returns a Resolver listing names through c.
+func New(c *toggl.Client) *Resolver {
+	return &Resolver{
+		client:   c,
+		now:      time.Now,
+		indexes:  make(map[int]*index),
+		listings: make(map[int]*listing),
+	}
+}
+
+// Invalidate forgets the names of a workspace, which are listed again on
+// the next lookup.
+func (r *Resolver) Invalidate(wid int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.indexes, wid)
+	delete(r.listings, wid)
+}
+
+// ClientID returns the ID of the client with the given name.
+func (r *Resolver) ClientID(wid int, name string) (int, error) {
+	return r.ClientIDContext(context.Background(), wid, name)
+}
+
+// ClientIDContext is like ClientID, but with the provided context.
+func (r *Resolver) ClientIDContext(ctx context.Context, wid int, name string) (int, error) {
+	return r.lookup(ctx, wid, KindClient, name)
+}
+
+// ProjectID returns the ID of the project with the given path, either
+// "Project" or "Client / Project".
+func (r *Resolver) ProjectID(wid int, path string) (int, error) {
+	return r.ProjectIDContext(context.Background(), wid, path)
+}
+
+// ProjectIDContext is like ProjectID, but with the provided context.
+func (r *Resolver) ProjectIDContext(ctx context.Context, wid int, path string) (int, error) {
+	return r.lookup(ctx, wid, KindProject, path)
+}
+
+// TaskID returns the ID of the task with the given path, either
+// "Project / Task" or "Client / Project / Task".
+func (r *Resolver) TaskID(wid int, path string) (int, error) {
+	return r.TaskIDContext(context.Background(), wid, path)
+}
+
+// TaskIDContext is like TaskID, but with the provided context.
+func (r *Resolver) TaskIDContext(ctx context.Context, wid int, path string) (int, error) {
+	return r.lookup(ctx, wid, KindTask, path)
+}
+
+// TagID returns the ID of the tag with the given name.
+func (r *Resolver) TagID(wid int, name string) (int, error) {
+	return r.TagIDContext(context.Background(), wid, name)
+}
+
+// TagIDContext is like TagID, but with the provided context.
+func (r *Resolver) TagIDContext(ctx context.Context, wid int, name string) (int, error) {
+	return r.lookup(ctx, wid, KindTag, name)
+}
+
+func (r *Resolver) lookup(ctx context.Context, wid int, kind, name string) (int, error) {
+	idx, err := r.index(ctx, wid)
+	if err != nil {
+		return 0, err
+	}
+	return idx.lookup(kind, name)
+}
+
+// index returns the names of a workspace, listing them if needed. Lookups
+// of a workspace whose names are being listed wait for them.
+func (r *Resolver) index(ctx context.Context, wid int) (*index, error) {
+	r.mu.Lock()
+	idx := r.indexes[wid]
+	if idx != nil && (r.MaxAge <= 0 || r.now().Sub(idx.built) < r.MaxAge) {
+		r.mu.Unlock()
+		return idx, nil
+	}
+	l := r.listings[wid]
+	if l != nil {
+		r.mu.Unlock()
+		select {
+		case <-l.done:
+			return l.idx, l.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	l = &listing{done: make(chan struct{})}
+	r.listings[wid] = l
+	r.mu.Unlock()
+
+	l.idx, l.err = r.build(ctx, wid)
+
+	r.mu.Lock()
+	if r.listings[wid] == l {
+		delete(r.listings, wid)
+		if l.err == nil {
+			r.indexes[wid] = l.idx
+		}
+	}
+	r.mu.Unlock()
+	close(l.done)
+	return l.idx, l.err
+}
+
+// build lists the names of a workspace.
+func (r *Resolver) build(ctx context.Context, wid int) (*index, error) {
+	ws := r.client.Workspaces
+	clients, err := ws.ListClientsContext(ctx, wid)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := ws.ListProjectsContext(ctx, wid, "")
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := ws.ListTasksContext(ctx, wid, "")
+	if err != nil {
+		return nil, err
+	}
+	tags, err := ws.ListTagsContext(ctx, wid)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := newIndex(r.now())
+	clientNames := make(map[int]string)
+	for _, c := range clients {
+		clientNames[c.ID] = c.Name
+		idx.add(KindClient, c.ID, c.Name)
+	}
+	projectPaths := make(map[int][]string)
+	for _, p := range projects {
+		path := []string{p.Name}
+		if name, ok := clientNames[p.ClientID]; ok {
+			path = []string{name, p.Name}
+		}
+		projectPaths[p.ID] = path
+		idx.add(KindProject, p.ID, path...)
+	}
+	for _, t := range tasks {
+		if path, ok := projectPaths[t.ProjectID]; ok {
+			idx.add(KindTask, t.ID, append(append([]string(nil), path...), t.Name)...)
+		}
+	}
+	for _, t := range tags {
+		idx.add(KindTag, t.ID, t.Name)
+	}
+	return idx, nil
+}
+
+// entry is a named object.
+type entry struct {
+	id   int
+	path string // complete path
+}
+
+// index holds the objects of a workspace by kind and normalized path.
+type index struct {
+	built time.Time
+	kinds map[string]map[string][]entry
+}
+
+func newIndex(built time.Time) *index {
+	return &index{built: built, kinds: make(map[string]map[string][]entry)}
+}
+
+// add indexes an object by its complete path and by the path without its
+// leading client, if any.
+func (idx *index) add(kind string, id int, path ...string) {
+	names := idx.kinds[kind]
+	if names == nil {
+		names = make(map[string][]entry)
+		idx.kinds[kind] = names
+	}
+
+	e := entry{id: id, path: strings.Join(path, " / ")}
+	keys := []string{normalize(e.path)}
+	if (kind == KindProject && len(path) == 2) || (kind == KindTask && len(path) == 3) {
+		keys = append(keys, normalize(strings.Join(path[1:], " / ")))
+	}
+	for _, k := range keys {
+		names[k] = append(names[k], e)
+	}
+}
+
+func (idx *index) lookup(kind, name string) (int, error) {
+	key := normalize(name)
+	matches := idx.kinds[kind][key]
+
+	switch len(matches) {
+	case 0:
+		return 0, &NotFoundError{Kind: kind, Name: name, Suggestions: idx.suggest(kind, key)}
+	case 1:
+		return matches[0].id, nil
+	}
+
+	paths := make([]string, len(matches))
+	for i, e := range matches {
+		paths[i] = e.path
+	}
+	sort.Strings(paths)
+	return 0, &AmbiguousError{Kind: kind, Name: name, Matches: paths}
+}
+
+// suggest returns the complete paths of the objects whose names are
+// closest to key.
+func (idx *index) suggest(kind, key string) []string {
+	type suggestion struct {
+		path string
+		dist int
+	}
+	max := len([]rune(key))/3 + 1
+	best := make(map[string]int)
+	for k, entries := range idx.kinds[kind] {
+		d := levenshtein(key, k)
+		if d > max {
+			continue
+		}
+		for _, e := range entries {
+			if old, ok := best[e.path]; !ok || d < old {
+				best[e.path] = d
+			}
+		}
+	}
+
+	var list []suggestion
+	for path, d := range best {
+		list = append(list, suggestion{path, d})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].dist != list[j].dist {
+			return list[i].dist < list[j].dist
+		}
+		return list[i].path < list[j].path
+	})
+	if len(list) > MaxSuggestions {
+		list = list[:MaxSuggestions]
+	}
+
+	var paths []string
+	for _, s := range list {
+		paths = append(paths, s.path)
+	}
+	return paths
+}
+
+// normalize returns the lookup key of a path: lower case, with single
+// spaces and " / " between names.
+func normalize(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		parts[i] = strings.ToLower(strings.Join(strings.Fields(p), " "))
+	}
+	return strings.Join(parts, " / ")
+}
+
+// levenshtein returns the edit distance between a and b, in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,152 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resolve
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+	"github.com/gedex/go-toggl/toggl/toggltest"
+)
+
+func TestResolver(t *testing.T) {
+	s := toggltest.NewServer()
+	defer s.Close()
+	wid := s.User().DefautWID
+
+	acme := s.AddClient(toggl.WorkspaceClient{WorkspaceID: wid, Name: "Acme"})
+	globex := s.AddClient(toggl.WorkspaceClient{WorkspaceID: wid, Name: "Globex"})
+	redesign := s.AddProject(toggl.Project{WorkspaceID: wid, ClientID: acme.ID, Name: "Website redesign"})
+	acmeSupport := s.AddProject(toggl.Project{WorkspaceID: wid, ClientID: acme.ID, Name: "Support"})
+	globexSupport := s.AddProject(toggl.Project{WorkspaceID: wid, ClientID: globex.ID, Name: "Support"})
+	internal := s.AddProject(toggl.Project{WorkspaceID: wid, Name: "Internal"})
+	qa := s.AddTask(toggl.Task{WorkspaceID: wid, ProjectID: redesign.ID, Name: "QA"})
+	s.AddTask(toggl.Task{WorkspaceID: wid, ProjectID: acmeSupport.ID, Name: "Triage"})
+	globexTriage := s.AddTask(toggl.Task{WorkspaceID: wid, ProjectID: globexSupport.ID, Name: "Triage"})
+	tag := s.AddTag(toggl.Tag{WorkspaceID: wid, Name: "billed"})
+
+	r := New(s.Client())
+	tests := []struct {
+		lookup func(int, string) (int, error)
+		name   string
+		want   int
+	}{
+		{r.ClientID, "acme", acme.ID},
+		{r.ProjectID, "Acme / Website redesign", redesign.ID},
+		{r.ProjectID, "  website   REDESIGN ", redesign.ID},
+		{r.ProjectID, "globex/support", globexSupport.ID},
+		{r.ProjectID, "Internal", internal.ID},
+		{r.TaskID, "Acme / Website redesign / QA", qa.ID},
+		{r.TaskID, "website redesign/qa", qa.ID},
+		{r.TaskID, "Globex / Support / Triage", globexTriage.ID},
+		{r.TagID, "Billed", tag.ID},
+	}
+	for _, tt := range tests {
+		got, err := tt.lookup(wid, tt.name)
+		if err != nil || got != tt.want {
+			t.Errorf("lookup of %q returned %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+
+	_, err := r.TaskID(wid, "Support / Triage")
+	want := &AmbiguousError{Kind: KindTask, Name: "Support / Triage", Matches: []string{"Acme / Support / Triage", "Globex / Support / Triage"}}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("TaskID returned %v, want %v", err, want)
+	}
+
+	_, err = r.ProjectID(wid, "Acme / Website redesgin")
+	want2 := &NotFoundError{Kind: KindProject, Name: "Acme / Website redesgin", Suggestions: []string{"Acme / Website redesign"}}
+	if !reflect.DeepEqual(err, want2) {
+		t.Errorf("ProjectID returned %v, want %v", err, want2)
+	}
+	if err.Error() != `no project named "Acme / Website redesgin"; did you mean "Acme / Website redesign"?` {
+		t.Errorf("NotFoundError.Error returned %q", err)
+	}
+
+	_, err = r.ClientID(wid, "Initech")
+	if e, ok := err.(*NotFoundError); !ok || e.Suggestions != nil {
+		t.Errorf("ClientID returned %v, want *NotFoundError without suggestions", err)
+	}
+}
+
+func TestResolver_cache(t *testing.T) {
+	s := toggltest.NewServer()
+	defer s.Close()
+	wid := s.User().DefautWID
+	now := time.Date(2013, time.July, 1, 12, 0, 0, 0, time.UTC)
+
+	r := New(s.Client())
+	r.now = func() time.Time { return now }
+	r.MaxAge = time.Hour
+
+	if _, err := r.TagID(wid, "billed"); err == nil {
+		t.Fatalf("TagID of missing tag returned no error")
+	}
+	n := len(s.Requests())
+	tag := s.AddTag(toggl.Tag{WorkspaceID: wid, Name: "billed"})
+
+	if _, err := r.TagID(wid, "billed"); err == nil || len(s.Requests()) != n {
+		t.Errorf("TagID listed names again before MaxAge")
+	}
+
+	r.Invalidate(wid)
+	if id, err := r.TagID(wid, "billed"); err != nil || id != tag.ID {
+		t.Errorf("TagID after Invalidate returned %v, %v, want %v", id, err, tag.ID)
+	}
+
+	s.AddClient(toggl.WorkspaceClient{WorkspaceID: wid, Name: "Acme"})
+	now = now.Add(time.Hour)
+	if _, err := r.ClientID(wid, "Acme"); err != nil {
+		t.Errorf("ClientID after MaxAge returned error: %v", err)
+	}
+}
+
+func TestResolver_concurrent(t *testing.T) {
+	s := toggltest.NewServer()
+	defer s.Close()
+	wid := s.User().DefautWID
+	wc := s.AddClient(toggl.WorkspaceClient{WorkspaceID: wid, Name: "Acme"})
+
+	r := New(s.Client())
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if id, err := r.ClientID(wid, "Acme"); err != nil || id != wc.ID {
+				t.Errorf("ClientID returned %v, %v, want %v", id, err, wc.ID)
+			}
+		}()
+	}
+	wg.Wait()
+
+	n := len(s.Requests())
+	r.Invalidate(wid)
+	r.ClientID(wid, "Acme")
+	if want := len(s.Requests()) - n; n != want {
+		t.Errorf("Concurrent lookups sent %d requests, want %d", n, want)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) returned %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
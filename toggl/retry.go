@@ -0,0 +1,133 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggl
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMinBackoff = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy describes how requests that were throttled (429), failed
+// with a server error (5xx) or failed to reach Toggl are retried.
+//
+// Only idempotent requests (GET, HEAD, PUT, DELETE and OPTIONS) are retried
+// unless RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// Maximum number of times a request is sent, including the first
+	// attempt. Values below 2 disable retries.
+	MaxAttempts int
+
+	// Delay before the first retry. The delay doubles on every further
+	// attempt and is randomized by up to half its value. Defaults to 500ms.
+	MinBackoff time.Duration
+
+	// Upper bound of the exponential backoff. Defaults to 30s. A delay
+	// requested by the Retry-After header is honored even when larger.
+	MaxBackoff time.Duration
+
+	// Whether POST and PATCH requests may be retried. Enabling this can
+	// create duplicates when a request reached Toggl but its response
+	// did not reach the client.
+	RetryNonIdempotent bool
+}
+
+// shouldRetry reports whether req should be sent again after the given
+// attempt produced resp, which is nil if the request could not be sent.
+func (p *RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, attempt int) bool {
+	if p == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+
+	switch req.Method {
+	case "GET", "HEAD", "PUT", "DELETE", "OPTIONS":
+	default:
+		if !p.RetryNonIdempotent {
+			return false
+		}
+	}
+
+	if resp == nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// backoff returns how long to wait before the attempt following the given
+// one. A Retry-After header on resp takes precedence.
+func (p *RetryPolicy) backoff(resp *http.Response, attempt int) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return d
+		}
+	}
+
+	min, max := p.MinBackoff, p.MaxBackoff
+	if min <= 0 {
+		min = defaultMinBackoff
+	}
+	if max <= 0 {
+		max = defaultMaxBackoff
+	}
+
+	d := min
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// retryAfter parses the value of a Retry-After header, which is either
+// a number of seconds or an HTTP date.
+func retryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// rewindRequest returns a copy of req, which has already been sent, that
+// can be sent again.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("request body cannot be rewound")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next.Body = body
+	return next, nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggl
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDo_retryRateLimited(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+
+	attempts := 0
+	mux.HandleFunc("/time_entries/1", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id": 1}}`)
+	})
+
+	result, err := client.TimeEntries.Get(1)
+	if err != nil {
+		t.Errorf("TimeEntries.Get returned error: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("TimeEntries.Get sent %d requests, want 2", attempts)
+	}
+
+	want := &TimeEntry{ID: 1}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("TimeEntries.Get returned %v, want %v", result, want)
+	}
+}
+
+func TestDo_retryServerErrorExhausted(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+
+	attempts := 0
+	mux.HandleFunc("/time_entries/1", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+	})
+
+	_, err := client.TimeEntries.Get(1)
+	if statusCode(err) != http.StatusServiceUnavailable {
+		t.Errorf("TimeEntries.Get returned error %v, want 503 ErrorResponse", err)
+	}
+	if attempts != 3 {
+		t.Errorf("TimeEntries.Get sent %d requests, want 3", attempts)
+	}
+}
+
+func TestDo_noRetryWithoutPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	attempts := 0
+	mux.HandleFunc("/time_entries/1", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+	})
+
+	_, err := client.TimeEntries.Get(1)
+	if !IsRateLimited(err) {
+		t.Errorf("IsRateLimited(%v) = false, want true", err)
+	}
+	if attempts != 1 {
+		t.Errorf("TimeEntries.Get sent %d requests, want 1", attempts)
+	}
+}
+
+func TestDo_retryNonIdempotent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var bodies []string
+	mux.HandleFunc("/time_entries", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) == 1 {
+			http.Error(w, "Bad Gateway", http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id": 1}}`)
+	})
+
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+	if _, err := client.TimeEntries.Create(&TimeEntry{ProjectID: 1}); err == nil {
+		t.Errorf("TimeEntries.Create retried a POST without RetryNonIdempotent")
+	}
+
+	bodies = nil
+	client.RetryPolicy.RetryNonIdempotent = true
+	if _, err := client.TimeEntries.Create(&TimeEntry{ProjectID: 1}); err != nil {
+		t.Errorf("TimeEntries.Create returned error: %v", err)
+	}
+	if len(bodies) != 2 || bodies[0] != bodies[1] {
+		t.Errorf("TimeEntries.Create sent bodies %q, want the same body twice", bodies)
+	}
+}
+
+func TestDo_retryCanceledDuringBackoff(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Hour}
+
+	mux.HandleFunc("/time_entries/1", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := client.TimeEntries.GetContext(ctx, 1)
+	if err != context.DeadlineExceeded {
+		t.Errorf("TimeEntries.GetContext returned error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := &RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{10, 500 * time.Millisecond, time.Second},
+	}
+
+	for _, tt := range tests {
+		if d := p.backoff(nil, tt.attempt); d < tt.min || d > tt.max {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", tt.attempt, d, tt.min, tt.max)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"120"}}}
+	if d := p.backoff(resp, 1); d != 2*time.Minute {
+		t.Errorf("backoff with Retry-After = %v, want %v", d, 2*time.Minute)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2013, time.July, 13, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{now.Add(time.Minute).Format(http.TimeFormat), time.Minute, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+	}
+
+	for _, tt := range tests {
+		d, ok := retryAfter(tt.value, now)
+		if d != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %v, %v, want %v, %v", tt.value, d, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// SessionCookieName is the name of the cookie holding a Toggl API session.
+const SessionCookieName = "toggl_api_session_new"
+
+// SessionsService handles communication with the session related
+// methods of the Toggl API.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/authentication.md#session-cookie
+type SessionsService struct {
+	client *Client
+}
+
+// Session represents an API session.
+type Session struct {
+	// Cookie to authenticate subsequent requests with, see SessionAuth
+	Cookie *http.Cookie
+
+	// User the session belongs to, including the user's API token
+	User *User
+}
+
+// Create a session using the client's current credentials, e.g. BasicAuth
+// with the user's email and password.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/authentication.md#session-cookie
+func (s *SessionsService) Create() (*Session, error) {
+	return s.CreateContext(context.Background())
+}
+
+// CreateContext is like Create, but with the provided context.
+func (s *SessionsService) CreateContext(ctx context.Context) (*Session, error) {
+	u := "sessions"
+	req, err := s.client.NewRequestContext(ctx, "POST", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data := new(UserResponse)
+	resp, err := s.client.Do(req, data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range resp.Cookies() {
+		if c.Name == SessionCookieName {
+			return &Session{Cookie: c, User: data.Data}, nil
+		}
+	}
+	return nil, errors.New("session cookie missing from response")
+}
+
+// Delete the session the client is authenticated with.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/authentication.md#destroy-the-session
+func (s *SessionsService) Delete() error {
+	return s.DeleteContext(context.Background())
+}
+
+// DeleteContext is like Delete, but with the provided context.
+func (s *SessionsService) DeleteContext(ctx context.Context) error {
+	u := "sessions"
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(req, nil)
+	return err
+}
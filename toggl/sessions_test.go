@@ -0,0 +1,74 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggl
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSessionsService_Create(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.SetAuthenticator(BasicAuth{Username: "user@example.com", Password: "secret"})
+
+	mux.HandleFunc("/sessions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		if user, pass, _ := r.BasicAuth(); user != "user@example.com" || pass != "secret" {
+			t.Errorf("Request basic auth = %v:%v, want user@example.com:secret", user, pass)
+		}
+		http.SetCookie(w, &http.Cookie{Name: SessionCookieName, Value: "s"})
+		fmt.Fprint(w, `{"data":{"id": 1, "api_token": "token"}}`)
+	})
+
+	result, err := client.Sessions.Create()
+	if err != nil {
+		t.Fatalf("Sessions.Create returned error: %v", err)
+	}
+
+	want := &User{ID: 1, APIToken: "token"}
+	if !reflect.DeepEqual(result.User, want) {
+		t.Errorf("Sessions.Create returned user %v, want %v", result.User, want)
+	}
+	if result.Cookie == nil || result.Cookie.Value != "s" {
+		t.Errorf("Sessions.Create returned cookie %v, want value s", result.Cookie)
+	}
+}
+
+func TestSessionsService_Create_missingCookie(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/sessions", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"id": 1}}`)
+	})
+
+	if _, err := client.Sessions.Create(); err == nil {
+		t.Errorf("Sessions.Create returned no error for a response without cookie")
+	}
+}
+
+func TestSessionsService_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.SetAuthenticator(SessionAuth{Cookie: &http.Cookie{Name: SessionCookieName, Value: "s"}})
+
+	mux.HandleFunc("/sessions", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		if c, err := r.Cookie(SessionCookieName); err != nil || c.Value != "s" {
+			t.Errorf("Request session cookie = %v, %v, want value s", c, err)
+		}
+	})
+
+	err := client.Sessions.Delete()
+	if err != nil {
+		t.Errorf("Sessions.Delete returned error: %v", err)
+	}
+}
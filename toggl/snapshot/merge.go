@@ -0,0 +1,57 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package snapshot
+
+import (
+	"reflect"
+	"sort"
+)
+
+// merge applies the objects of one kind received from Toggl to m, the
+// snapshot's map of that kind, and returns the changes ordered by ID.
+// Received objects for which deleted reports true are removed. If full is
+// set, the objects of m that were not received are removed as well, unless
+// keep reports they are out of the range Toggl returns. Removed objects
+// are returned in their last known state.
+func merge[T any](m map[int]T, list []T, id func(T) int, deleted func(T) bool, full bool, keep func(T) bool) (created, updated, removed []T) {
+	seen := make(map[int]bool, len(list))
+	var gone []int
+	for _, cur := range list {
+		k := id(cur)
+		seen[k] = true
+		old, known := m[k]
+		switch {
+		case deleted(cur):
+			if known {
+				gone = append(gone, k)
+			}
+		case !known:
+			created = append(created, cur)
+			m[k] = cur
+		case !reflect.DeepEqual(old, cur):
+			updated = append(updated, cur)
+			m[k] = cur
+		}
+	}
+
+	if full {
+		for k, old := range m {
+			if !seen[k] && (keep == nil || !keep(old)) {
+				gone = append(gone, k)
+			}
+		}
+	}
+	sort.Ints(gone)
+	for _, k := range gone {
+		removed = append(removed, m[k])
+		delete(m, k)
+	}
+
+	for _, l := range [][]T{created, updated} {
+		sort.SliceStable(l, func(i, j int) bool { return id(l[i]) < id(l[j]) })
+	}
+	return created, updated, removed
+}
@@ -0,0 +1,166 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package snapshot keeps a local copy of a Toggl account in sync.
+
+A Syncer pulls a complete Snapshot of the user's workspaces, clients,
+projects, tasks, tags and time entries on the first call to Sync, and only
+the objects changed since then on subsequent calls. Each call reports
+which objects were created, updated or deleted:
+
+	s := snapshot.NewSyncer(c, nil)
+	for range time.Tick(time.Minute) {
+		changes, err := s.Sync()
+		if err != nil {
+			return err
+		}
+		for _, te := range changes.TimeEntries.Created {
+			fmt.Println("new time entry", te.Description)
+		}
+	}
+
+Snapshots can be saved and loaded to resume syncing where it was left off:
+
+	err := s.Snapshot().Save(f)
+	...
+	snap, err := snapshot.Load(f)
+	s := snapshot.NewSyncer(c, snap)
+*/
+package snapshot
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+// TimeEntryWindow is how far back Toggl returns time entries in a full
+// snapshot. Older entries are kept by Resync even though they are missing
+// from the response.
+const TimeEntryWindow = 9 * 24 * time.Hour
+
+// Snapshot represents the state of a Toggl account, with objects by ID.
+type Snapshot struct {
+	// Server time of the snapshot, as Unix time, from which changes are
+	// fetched on the next sync. 0 if the snapshot was never synced.
+	Since int `json:"since"`
+
+	// User without related data
+	User toggl.User `json:"user"`
+
+	Workspaces  map[int]toggl.Workspace       `json:"workspaces"`
+	Clients     map[int]toggl.WorkspaceClient `json:"clients"`
+	Projects    map[int]toggl.Project         `json:"projects"`
+	Tasks       map[int]toggl.Task            `json:"tasks"`
+	Tags        map[int]toggl.Tag             `json:"tags"`
+	TimeEntries map[int]toggl.TimeEntry       `json:"time_entries"`
+}
+
+// New returns an empty Snapshot.
+func New() *Snapshot {
+	return &Snapshot{
+		Workspaces:  make(map[int]toggl.Workspace),
+		Clients:     make(map[int]toggl.WorkspaceClient),
+		Projects:    make(map[int]toggl.Project),
+		Tasks:       make(map[int]toggl.Task),
+		Tags:        make(map[int]toggl.Tag),
+		TimeEntries: make(map[int]toggl.TimeEntry),
+	}
+}
+
+// Load reads a Snapshot saved with Save.
+func Load(r io.Reader) (*Snapshot, error) {
+	s := New()
+	if err := json.NewDecoder(r).Decode(s); err != nil {
+		return nil, err
+	}
+	s.init()
+	return s, nil
+}
+
+// Save writes s as JSON.
+func (s *Snapshot) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+// init allocates the maps left nil, e.g. by decoding.
+func (s *Snapshot) init() {
+	e := New()
+	if s.Workspaces == nil {
+		s.Workspaces = e.Workspaces
+	}
+	if s.Clients == nil {
+		s.Clients = e.Clients
+	}
+	if s.Projects == nil {
+		s.Projects = e.Projects
+	}
+	if s.Tasks == nil {
+		s.Tasks = e.Tasks
+	}
+	if s.Tags == nil {
+		s.Tags = e.Tags
+	}
+	if s.TimeEntries == nil {
+		s.TimeEntries = e.TimeEntries
+	}
+}
+
+// Changes reports the objects created, updated and deleted by a sync.
+type Changes struct {
+	// Whether a complete snapshot was fetched
+	Full bool
+
+	Workspaces  WorkspaceChanges
+	Clients     ClientChanges
+	Projects    ProjectChanges
+	Tasks       TaskChanges
+	Tags        TagChanges
+	TimeEntries TimeEntryChanges
+}
+
+// Empty reports whether no objects changed.
+func (c *Changes) Empty() bool {
+	return len(c.Workspaces.Created)+len(c.Workspaces.Updated)+len(c.Workspaces.Deleted)+
+		len(c.Clients.Created)+len(c.Clients.Updated)+len(c.Clients.Deleted)+
+		len(c.Projects.Created)+len(c.Projects.Updated)+len(c.Projects.Deleted)+
+		len(c.Tasks.Created)+len(c.Tasks.Updated)+len(c.Tasks.Deleted)+
+		len(c.Tags.Created)+len(c.Tags.Updated)+len(c.Tags.Deleted)+
+		len(c.TimeEntries.Created)+len(c.TimeEntries.Updated)+len(c.TimeEntries.Deleted) == 0
+}
+
+// WorkspaceChanges reports changed workspaces. Deleted holds the last
+// known state of deleted objects. All lists are ordered by ID.
+type WorkspaceChanges struct {
+	Created, Updated, Deleted []toggl.Workspace
+}
+
+// ClientChanges reports changed clients, like WorkspaceChanges.
+type ClientChanges struct {
+	Created, Updated, Deleted []toggl.WorkspaceClient
+}
+
+// ProjectChanges reports changed projects, like WorkspaceChanges.
+type ProjectChanges struct {
+	Created, Updated, Deleted []toggl.Project
+}
+
+// TaskChanges reports changed tasks, like WorkspaceChanges.
+type TaskChanges struct {
+	Created, Updated, Deleted []toggl.Task
+}
+
+// TagChanges reports changed tags, like WorkspaceChanges.
+type TagChanges struct {
+	Created, Updated, Deleted []toggl.Tag
+}
+
+// TimeEntryChanges reports changed time entries, like WorkspaceChanges.
+type TimeEntryChanges struct {
+	Created, Updated, Deleted []toggl.TimeEntry
+}
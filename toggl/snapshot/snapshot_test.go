@@ -0,0 +1,144 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package snapshot
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+	"github.com/gedex/go-toggl/toggl/toggltest"
+)
+
+// ids returns the IDs of a list of Toggl objects.
+func ids(list interface{}) []int {
+	v := reflect.ValueOf(list)
+	ids := []int{}
+	for i := 0; i < v.Len(); i++ {
+		ids = append(ids, int(v.Index(i).FieldByName("ID").Int()))
+	}
+	return ids
+}
+
+func TestSyncer(t *testing.T) {
+	s := toggltest.NewServer()
+	defer s.Close()
+	now := time.Date(2013, time.July, 1, 12, 0, 0, 0, time.UTC)
+	s.SetClock(func() time.Time { return now })
+
+	wid := s.User().DefautWID
+	client := s.AddClient(toggl.WorkspaceClient{WorkspaceID: wid, Name: "Acme"})
+	project := s.AddProject(toggl.Project{WorkspaceID: wid, ClientID: client.ID, Name: "Website"})
+	task := s.AddTask(toggl.Task{WorkspaceID: wid, ProjectID: project.ID, Name: "QA"})
+	tag := s.AddTag(toggl.Tag{WorkspaceID: wid, Name: "billed"})
+	te := s.AddTimeEntry(toggl.TimeEntry{WorkspaceID: wid, ProjectID: project.ID, Start: &now, Duration: 60, CreatedWith: "test"})
+
+	syncer := NewSyncer(s.Client(), nil)
+	ch, err := syncer.Sync()
+	if err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+	if !ch.Full || !reflect.DeepEqual(ids(ch.Workspaces.Created), []int{wid}) ||
+		!reflect.DeepEqual(ids(ch.Clients.Created), []int{client.ID}) ||
+		!reflect.DeepEqual(ids(ch.Projects.Created), []int{project.ID}) ||
+		!reflect.DeepEqual(ids(ch.Tasks.Created), []int{task.ID}) ||
+		!reflect.DeepEqual(ids(ch.Tags.Created), []int{tag.ID}) ||
+		!reflect.DeepEqual(ids(ch.TimeEntries.Created), []int{te.ID}) {
+		t.Errorf("First Sync returned %+v, want everything created", ch)
+	}
+	snap := syncer.Snapshot()
+	if snap.Since != int(now.Unix()) || snap.User.ID != s.User().ID || snap.User.Clients != nil {
+		t.Errorf("Snapshot has since %d and user %+v", snap.Since, snap.User)
+	}
+
+	// Nothing changed
+	if ch, err = syncer.Sync(); err != nil || ch.Full || !ch.Empty() {
+		t.Errorf("Sync without changes returned %+v, %v", ch, err)
+	}
+
+	now = now.Add(time.Minute)
+	c := s.Client()
+	if _, err := c.Clients.Update(&toggl.WorkspaceClient{ID: client.ID, Name: "Acme Corp"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Tasks.Delete(task.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Tags.Delete(tag.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.TimeEntries.Delete(te.ID); err != nil {
+		t.Fatal(err)
+	}
+	created, err := c.TimeEntries.Create(&toggl.TimeEntry{WorkspaceID: wid, Start: &now, Duration: 60, CreatedWith: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(time.Minute)
+	ch, err = syncer.Sync()
+	if err != nil {
+		t.Fatalf("Sync returned error: %v", err)
+	}
+	if ch.Full || len(ch.Clients.Updated) != 1 || ch.Clients.Updated[0].Name != "Acme Corp" ||
+		!reflect.DeepEqual(ids(ch.Tasks.Deleted), []int{task.ID}) || ch.Tasks.Deleted[0].Name != "QA" ||
+		!reflect.DeepEqual(ids(ch.Tags.Deleted), []int{tag.ID}) ||
+		!reflect.DeepEqual(ids(ch.TimeEntries.Deleted), []int{te.ID}) ||
+		!reflect.DeepEqual(ids(ch.TimeEntries.Created), []int{created.ID}) ||
+		len(ch.Projects.Created)+len(ch.Projects.Updated)+len(ch.Workspaces.Updated) != 0 {
+		t.Errorf("Sync returned %+v", ch)
+	}
+	if _, ok := snap.Tasks[task.ID]; ok || snap.Clients[client.ID].Name != "Acme Corp" || len(snap.TimeEntries) != 1 {
+		t.Errorf("Sync left snapshot %+v", snap)
+	}
+
+	// Saved snapshots resume incrementally.
+	var buf bytes.Buffer
+	if err := snap.Save(&buf); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, snap) {
+		t.Errorf("Load returned %+v, want %+v", loaded, snap)
+	}
+	if ch, err := NewSyncer(c, loaded).Sync(); err != nil || ch.Full || !ch.Empty() {
+		t.Errorf("Sync of loaded snapshot returned %+v, %v", ch, err)
+	}
+}
+
+func TestSyncer_Resync(t *testing.T) {
+	s := toggltest.NewServer()
+	defer s.Close()
+	now := time.Date(2013, time.July, 1, 12, 0, 0, 0, time.UTC)
+	s.SetClock(func() time.Time { return now })
+	wid := s.User().DefautWID
+
+	old := now.Add(-30 * 24 * time.Hour)
+	snap := New()
+	snap.Since = int(now.Unix())
+	snap.Projects[1000] = toggl.Project{ID: 1000, Name: "Gone"}
+	snap.TimeEntries[1001] = toggl.TimeEntry{ID: 1001, Start: &old}
+	snap.TimeEntries[1002] = toggl.TimeEntry{ID: 1002, Start: &now}
+	p := s.AddProject(toggl.Project{WorkspaceID: wid, Name: "New"})
+
+	ch, err := NewSyncer(s.Client(), snap).Resync()
+	if err != nil {
+		t.Fatalf("Resync returned error: %v", err)
+	}
+	if !ch.Full || !reflect.DeepEqual(ids(ch.Projects.Deleted), []int{1000}) ||
+		!reflect.DeepEqual(ids(ch.Projects.Created), []int{p.ID}) ||
+		!reflect.DeepEqual(ids(ch.TimeEntries.Deleted), []int{1002}) {
+		t.Errorf("Resync returned %+v", ch)
+	}
+	if _, ok := snap.TimeEntries[1001]; !ok {
+		t.Errorf("Resync deleted a time entry older than TimeEntryWindow")
+	}
+}
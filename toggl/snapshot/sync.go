@@ -0,0 +1,120 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+// Syncer keeps a Snapshot in sync with a Toggl account. It is not safe for
+// concurrent use.
+type Syncer struct {
+	client *toggl.Client
+	snap   *Snapshot
+}
+
+// NewSyncer returns a Syncer updating snap through c. A new Snapshot is
+// used if snap is nil.
+func NewSyncer(c *toggl.Client, snap *Snapshot) *Syncer {
+	if snap == nil {
+		snap = New()
+	}
+	snap.init()
+	return &Syncer{client: c, snap: snap}
+}
+
+// Snapshot returns the snapshot being synced, which is updated in place.
+func (s *Syncer) Snapshot() *Snapshot {
+	return s.snap
+}
+
+// Sync fetches the objects changed since the last sync and applies them
+// to the snapshot. The first sync of a snapshot fetches all objects, which
+// are reported as created.
+func (s *Syncer) Sync() (*Changes, error) {
+	return s.SyncContext(context.Background())
+}
+
+// SyncContext is like Sync, but with the provided context.
+func (s *Syncer) SyncContext(ctx context.Context) (*Changes, error) {
+	if s.snap.Since == 0 {
+		return s.ResyncContext(ctx)
+	}
+
+	resp, err := s.client.Users.MeSinceContext(ctx, s.snap.Since)
+	if err != nil {
+		return nil, err
+	}
+	return s.apply(resp, false)
+}
+
+// Resync fetches all objects and replaces the snapshot with them.
+// Objects missing from the response are reported as deleted, except for
+// time entries older than TimeEntryWindow.
+func (s *Syncer) Resync() (*Changes, error) {
+	return s.ResyncContext(context.Background())
+}
+
+// ResyncContext is like Resync, but with the provided context.
+func (s *Syncer) ResyncContext(ctx context.Context) (*Changes, error) {
+	resp, err := s.client.Users.MeSinceContext(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	return s.apply(resp, true)
+}
+
+// apply applies resp to the snapshot. If full is set, resp holds all
+// objects.
+func (s *Syncer) apply(resp *toggl.UserResponse, full bool) (*Changes, error) {
+	if resp == nil || resp.Data == nil {
+		return nil, errors.New("empty user response")
+	}
+	u := *resp.Data
+	snap := s.snap
+	ch := &Changes{Full: full}
+
+	since := resp.Since
+	if since == 0 {
+		since = int(time.Now().Unix())
+	}
+
+	w, c, p := &ch.Workspaces, &ch.Clients, &ch.Projects
+	w.Created, w.Updated, w.Deleted = merge(snap.Workspaces, u.Workspaces,
+		func(w toggl.Workspace) int { return w.ID },
+		func(toggl.Workspace) bool { return false }, full, nil)
+	c.Created, c.Updated, c.Deleted = merge(snap.Clients, u.Clients,
+		func(c toggl.WorkspaceClient) int { return c.ID },
+		func(c toggl.WorkspaceClient) bool { return c.ServerDeletedAt != nil }, full, nil)
+	p.Created, p.Updated, p.Deleted = merge(snap.Projects, u.Projects,
+		func(p toggl.Project) int { return p.ID },
+		func(p toggl.Project) bool { return p.ServerDeletedAt != nil }, full, nil)
+
+	tk, tg, te := &ch.Tasks, &ch.Tags, &ch.TimeEntries
+	tk.Created, tk.Updated, tk.Deleted = merge(snap.Tasks, u.Tasks,
+		func(t toggl.Task) int { return t.ID },
+		func(t toggl.Task) bool { return t.ServerDeletedAt != nil }, full, nil)
+	tg.Created, tg.Updated, tg.Deleted = merge(snap.Tags, u.Tags,
+		func(t toggl.Tag) int { return t.ID },
+		func(t toggl.Tag) bool { return t.ServerDeletedAt != nil }, full, nil)
+
+	// A full list of time entries only covers those started after cutoff.
+	cutoff := time.Unix(int64(since), 0).Add(-TimeEntryWindow)
+	te.Created, te.Updated, te.Deleted = merge(snap.TimeEntries, u.TimeEntries,
+		func(te toggl.TimeEntry) int { return te.ID },
+		func(te toggl.TimeEntry) bool { return te.ServerDeletedAt != nil }, full,
+		func(te toggl.TimeEntry) bool { return te.Start != nil && !te.Start.After(cutoff) })
+
+	u.TimeEntries, u.Projects, u.Tags = nil, nil, nil
+	u.Workspaces, u.Clients, u.Tasks = nil, nil, nil
+	snap.User = u
+	snap.Since = since
+	return ch, nil
+}
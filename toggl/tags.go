@@ -6,8 +6,10 @@
 package toggl
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 )
 
 // TagsService handles communication with the tags related
@@ -20,9 +22,10 @@ type TagsService struct {
 
 // Tag represents a tag.
 type Tag struct {
-	ID          int    `json:"id,omitempty"`
-	WorkspaceID int    `json:"wid,omitempty"`
-	Name        string `json:"name,omitempty"`
+	ID              int        `json:"id,omitempty"`
+	WorkspaceID     int        `json:"wid,omitempty"`
+	Name            string     `json:"name,omitempty"`
+	ServerDeletedAt *time.Time `json:"server_deleted_at,omitempty"` // set once the tag is deleted
 }
 
 // TagResponse acts as a response wrapper where response returns
@@ -40,9 +43,14 @@ type TagCreate struct {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/tags.md#create-tag
 func (s *TagsService) Create(t *Tag) (*Tag, error) {
+	return s.CreateContext(context.Background(), t)
+}
+
+// CreateContext is like Create, but with the provided context.
+func (s *TagsService) CreateContext(ctx context.Context, t *Tag) (*Tag, error) {
 	u := "tags"
 	tc := &TagCreate{t}
-	req, err := s.client.NewRequest("POST", u, tc)
+	req, err := s.client.NewRequestContext(ctx, "POST", u, tc)
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +65,11 @@ func (s *TagsService) Create(t *Tag) (*Tag, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/tags.md#update-a-tag
 func (s *TagsService) Update(t *Tag) (*Tag, error) {
+	return s.UpdateContext(context.Background(), t)
+}
+
+// UpdateContext is like Update, but with the provided context.
+func (s *TagsService) UpdateContext(ctx context.Context, t *Tag) (*Tag, error) {
 	if t == nil {
 		return nil, errors.New("Tag cannot be nil")
 	}
@@ -67,7 +80,7 @@ func (s *TagsService) Update(t *Tag) (*Tag, error) {
 	u := fmt.Sprintf("tags/%v", t.ID)
 
 	tc := &TagCreate{t}
-	req, err := s.client.NewRequest("PUT", u, tc)
+	req, err := s.client.NewRequestContext(ctx, "PUT", u, tc)
 	if err != nil {
 		return nil, err
 	}
@@ -82,8 +95,13 @@ func (s *TagsService) Update(t *Tag) (*Tag, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/tags.md#delete-a-tag
 func (s *TagsService) Delete(id int) error {
+	return s.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is like Delete, but with the provided context.
+func (s *TagsService) DeleteContext(ctx context.Context, id int) error {
 	u := fmt.Sprintf("tags/%v", id)
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, nil)
 	if err != nil {
 		return err
 	}
@@ -6,6 +6,7 @@
 package toggl
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -29,6 +30,7 @@ type Task struct {
 	EstimatedSeconds int        `json:"estimated_seconds,omitempty"`
 	Active           bool       `json:"active,omitempty"`
 	At               *time.Time `json:"at,omitempty"`
+	ServerDeletedAt  *time.Time `json:"server_deleted_at,omitempty"` // set once the task is deleted
 }
 
 // TaskResponse acts as a response wrapper where response returns
@@ -52,9 +54,14 @@ type TaskCreate struct {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/tasks.md#actions-for-single-project-user
 func (s *TasksService) Create(t *Task) (*Task, error) {
+	return s.CreateContext(context.Background(), t)
+}
+
+// CreateContext is like Create, but with the provided context.
+func (s *TasksService) CreateContext(ctx context.Context, t *Task) (*Task, error) {
 	u := "tasks"
 	tc := &TaskCreate{t}
-	req, err := s.client.NewRequest("POST", u, tc)
+	req, err := s.client.NewRequestContext(ctx, "POST", u, tc)
 	if err != nil {
 		return nil, err
 	}
@@ -69,8 +76,13 @@ func (s *TasksService) Create(t *Task) (*Task, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/tasks.md#get-task-details
 func (s *TasksService) Get(id int) (*Task, error) {
+	return s.GetContext(context.Background(), id)
+}
+
+// GetContext is like Get, but with the provided context.
+func (s *TasksService) GetContext(ctx context.Context, id int) (*Task, error) {
 	u := fmt.Sprintf("tasks/%v", id)
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -85,6 +97,11 @@ func (s *TasksService) Get(id int) (*Task, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/tasks.md#update-a-task
 func (s *TasksService) Update(t *Task) (*Task, error) {
+	return s.UpdateContext(context.Background(), t)
+}
+
+// UpdateContext is like Update, but with the provided context.
+func (s *TasksService) UpdateContext(ctx context.Context, t *Task) (*Task, error) {
 	if t == nil {
 		return nil, errors.New("Task cannot be nil")
 	}
@@ -95,7 +112,7 @@ func (s *TasksService) Update(t *Task) (*Task, error) {
 	u := fmt.Sprintf("tasks/%v", t.ID)
 
 	tc := &TaskCreate{t}
-	req, err := s.client.NewRequest("PUT", u, tc)
+	req, err := s.client.NewRequestContext(ctx, "PUT", u, tc)
 	if err != nil {
 		return nil, err
 	}
@@ -106,14 +123,34 @@ func (s *TasksService) Update(t *Task) (*Task, error) {
 	return data.Data, err
 }
 
+// UpdateChecked updates a task, unless it changed since t was fetched.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/tasks.md#update-a-task
+func (s *TasksService) UpdateChecked(t *Task, merge TaskMergeFunc) (*Task, error) {
+	return s.UpdateCheckedContext(context.Background(), t, merge)
+}
+
+// UpdateCheckedContext is like UpdateChecked, but with the provided context.
+func (s *TasksService) UpdateCheckedContext(ctx context.Context, t *Task, merge TaskMergeFunc) (*Task, error) {
+	return updateChecked("Task", t, merge,
+		func(x *Task) (*int, *time.Time) { return &x.ID, x.At },
+		func(x *Task) (*Task, error) { return s.GetContext(ctx, x.ID) },
+		func(x *Task) (*Task, error) { return s.UpdateContext(ctx, x) })
+}
+
 // MassUpdate mass update tasks.
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/tasks.md#update-multiple-tasks
 func (s *TasksService) MassUpdate(ids string, t *Task) ([]Task, error) {
+	return s.MassUpdateContext(context.Background(), ids, t)
+}
+
+// MassUpdateContext is like MassUpdate, but with the provided context.
+func (s *TasksService) MassUpdateContext(ctx context.Context, ids string, t *Task) ([]Task, error) {
 	u := fmt.Sprintf("tasks/%v", ids)
 
 	tc := &TaskCreate{t}
-	req, err := s.client.NewRequest("PUT", u, tc)
+	req, err := s.client.NewRequestContext(ctx, "PUT", u, tc)
 	if err != nil {
 		return nil, err
 	}
@@ -124,12 +161,44 @@ func (s *TasksService) MassUpdate(ids string, t *Task) ([]Task, error) {
 	return data.Data, err
 }
 
+// MassUpdateIDs is like MassUpdate, but takes the task IDs as a list.
+// More than MaxIDsPerRequest ids are updated in several requests. If one
+// of them fails, the tasks updated so far are returned with the error.
+func (s *TasksService) MassUpdateIDs(ids []int, t *Task) ([]Task, error) {
+	return s.MassUpdateIDsContext(context.Background(), ids, t)
+}
+
+// MassUpdateIDsContext is like MassUpdateIDs, but with the provided context.
+func (s *TasksService) MassUpdateIDsContext(ctx context.Context, ids []int, t *Task) ([]Task, error) {
+	if t == nil {
+		return nil, errors.New("Task cannot be nil")
+	}
+	if err := validateIDs(ids); err != nil {
+		return nil, err
+	}
+
+	var tasks []Task
+	for _, chunk := range chunkIDs(ids) {
+		data, err := s.MassUpdateContext(ctx, joinIDs(chunk), t)
+		tasks = append(tasks, data...)
+		if err != nil {
+			return tasks, err
+		}
+	}
+	return tasks, nil
+}
+
 // Delete a task.
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/tasks.md#delete-a-task
 func (s *TasksService) Delete(id int) error {
+	return s.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is like Delete, but with the provided context.
+func (s *TasksService) DeleteContext(ctx context.Context, id int) error {
 	u := fmt.Sprintf("tasks/%v", id)
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, nil)
 	if err != nil {
 		return err
 	}
@@ -142,8 +211,13 @@ func (s *TasksService) Delete(id int) error {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/tasks.md#delete-multiple-tasks
 func (s *TasksService) MassDelete(ids string) error {
+	return s.MassDeleteContext(context.Background(), ids)
+}
+
+// MassDeleteContext is like MassDelete, but with the provided context.
+func (s *TasksService) MassDeleteContext(ctx context.Context, ids string) error {
 	u := fmt.Sprintf("tasks/%v", ids)
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, nil)
 	if err != nil {
 		return err
 	}
@@ -151,3 +225,23 @@ func (s *TasksService) MassDelete(ids string) error {
 	_, err = s.client.Do(req, nil)
 	return err
 }
+
+// MassDeleteIDs is like MassDelete, but takes the task IDs as a list.
+// More than MaxIDsPerRequest ids are deleted in several requests.
+func (s *TasksService) MassDeleteIDs(ids []int) error {
+	return s.MassDeleteIDsContext(context.Background(), ids)
+}
+
+// MassDeleteIDsContext is like MassDeleteIDs, but with the provided context.
+func (s *TasksService) MassDeleteIDsContext(ctx context.Context, ids []int) error {
+	if err := validateIDs(ids); err != nil {
+		return err
+	}
+
+	for _, chunk := range chunkIDs(ids) {
+		if err := s.MassDeleteContext(ctx, joinIDs(chunk)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
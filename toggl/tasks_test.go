@@ -10,7 +10,9 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestTasksService_Create(t *testing.T) {
@@ -91,6 +93,22 @@ func TestTasksService_Update(t *testing.T) {
 	}
 }
 
+func TestTasksService_UpdateChecked(t *testing.T) {
+	setup()
+	defer teardown()
+
+	at := time.Date(2013, time.July, 1, 12, 0, 0, 0, time.UTC)
+	mux.HandleFunc("/tasks/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data":{"id": 1, "name": "theirs", "at": "2013-07-01T12:05:00Z"}}`)
+	})
+
+	_, err := client.Tasks.UpdateChecked(&Task{ID: 1, At: &at}, nil)
+	if !IsConflict(err) {
+		t.Errorf("Tasks.UpdateChecked returned %v, want *ConflictError", err)
+	}
+}
+
 func TestTasksService_MassUpdate(t *testing.T) {
 	setup()
 	defer teardown()
@@ -120,6 +138,58 @@ func TestTasksService_MassUpdate(t *testing.T) {
 	}
 }
 
+func TestTasksService_MassUpdateIDs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ids := make([]int, MaxIDsPerRequest+1)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+
+	var paths []string
+	mux.HandleFunc("/tasks/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		paths = append(paths, r.URL.Path)
+
+		tids := strings.Split(strings.TrimPrefix(r.URL.Path, "/tasks/"), ",")
+		fmt.Fprintf(w, `{"data":[{"id": %v}]}`, tids[0])
+	})
+
+	result, err := client.Tasks.MassUpdateIDs(ids, &Task{Active: true})
+	if err != nil {
+		t.Errorf("Tasks.MassUpdateIDs returned error: %v", err)
+	}
+
+	wantPaths := []string{"/tasks/" + joinIDs(ids[:MaxIDsPerRequest]), fmt.Sprintf("/tasks/%d", len(ids))}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("Tasks.MassUpdateIDs requested %v, want %v", paths, wantPaths)
+	}
+
+	want := []Task{{ID: 1}, {ID: len(ids)}}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Tasks.MassUpdateIDs returned %v, want %v", result, want)
+	}
+}
+
+func TestTasksService_MassUpdateIDs_invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	if _, err := client.Tasks.MassUpdateIDs([]int{1, -2}, &Task{}); err == nil {
+		t.Errorf("Tasks.MassUpdateIDs returned no error for invalid ids")
+	}
+}
+
+func TestTasksService_MassUpdateIDs_nil(t *testing.T) {
+	setup()
+	defer teardown()
+
+	if _, err := client.Tasks.MassUpdateIDs([]int{1}, nil); err == nil {
+		t.Errorf("Tasks.MassUpdateIDs returned no error for a nil task")
+	}
+}
+
 func TestTasksService_Delete(t *testing.T) {
 	setup()
 	defer teardown()
@@ -147,3 +217,21 @@ func TestTasksService_MassDelete(t *testing.T) {
 		t.Errorf("Tasks.MassDelete returned error: %v", err)
 	}
 }
+
+func TestTasksService_MassDeleteIDs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/tasks/1,2", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+	})
+
+	err := client.Tasks.MassDeleteIDs([]int{1, 2})
+	if err != nil {
+		t.Errorf("Tasks.MassDeleteIDs returned error: %v", err)
+	}
+
+	if err := client.Tasks.MassDeleteIDs(nil); err == nil {
+		t.Errorf("Tasks.MassDeleteIDs returned no error for empty ids")
+	}
+}
@@ -6,6 +6,7 @@
 package toggl
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -22,18 +23,90 @@ type TimeEntriesService struct {
 
 // TimeEntry represents a time entry
 type TimeEntry struct {
-	ID          int        `json:"id,omitempty"`
-	WorkspaceID int        `json:"wid,omitempty"`
-	ProjectID   int        `json:"pid,omitempty"`
-	TaskID      int        `json:"tid,omitempty"`
-	Billable    bool       `json:"billable,omitempty"`
-	Start       *time.Time `json:"start,omitempty"`
-	Stop        *time.Time `json:"stop,omitempty"`
-	Duration    int        `json:"duration,omitempty"`
-	CreatedWith string     `json:"created_with,omitempty"`
-	Tags        []string   `json:"tags,omitempty"`
-	Duronly     bool       `json:"duronly,omitempty"`
-	At          int        `json:"at,omitempty"`
+	ID              int        `json:"id,omitempty"`
+	GUID            string     `json:"guid,omitempty"`
+	WorkspaceID     int        `json:"wid,omitempty"`
+	ProjectID       int        `json:"pid,omitempty"`
+	TaskID          int        `json:"tid,omitempty"`
+	UserID          int        `json:"uid,omitempty"`
+	Description     string     `json:"description,omitempty"`
+	Billable        bool       `json:"billable,omitempty"`
+	Start           *time.Time `json:"start,omitempty"`
+	Stop            *time.Time `json:"stop,omitempty"`
+	Duration        int        `json:"duration,omitempty"` // in seconds, negative while running; see Elapsed
+	CreatedWith     string     `json:"created_with,omitempty"`
+	Tags            []string   `json:"tags,omitempty"`
+	Duronly         bool       `json:"duronly,omitempty"`
+	At              *time.Time `json:"at,omitempty"`                // indicates the time entry was last updated
+	ServerDeletedAt *time.Time `json:"server_deleted_at,omitempty"` // set once the time entry is deleted
+
+	// TagAction tells MassUpdate whether Tags are added to or removed from
+	// the existing tags, see TagActionAdd and TagActionRemove. Tags are
+	// replaced when it is empty.
+	TagAction string `json:"tag_action,omitempty"`
+}
+
+// Tag actions for TimeEntriesService.MassUpdate.
+const (
+	TagActionAdd    = "add"
+	TagActionRemove = "remove"
+)
+
+// NewTimeEntry returns a stopped time entry starting at start and lasting
+// d, ready to be passed to TimeEntriesService.Create. d is truncated to
+// whole seconds and must be at least one second.
+func NewTimeEntry(start time.Time, d time.Duration) (*TimeEntry, error) {
+	d = d.Truncate(time.Second)
+	if d < time.Second {
+		return nil, fmt.Errorf("Invalid duration %v", d)
+	}
+
+	stop := start.Add(d)
+	return &TimeEntry{
+		Start:    &start,
+		Stop:     &stop,
+		Duration: int(d / time.Second),
+	}, nil
+}
+
+// NewDurationOnlyTimeEntry is like NewTimeEntry, but the returned entry
+// is marked so that Toggl shows only its duration, not its start and stop
+// time.
+func NewDurationOnlyTimeEntry(start time.Time, d time.Duration) (*TimeEntry, error) {
+	te, err := NewTimeEntry(start, d)
+	if err != nil {
+		return nil, err
+	}
+	te.Duronly = true
+	return te, nil
+}
+
+// NewRunningTimeEntry returns a time entry that started at start and is
+// still running, ready to be passed to TimeEntriesService.Create.
+func NewRunningTimeEntry(start time.Time) *TimeEntry {
+	return &TimeEntry{
+		Start:    &start,
+		Duration: int(-start.Unix()),
+	}
+}
+
+// IsRunning reports whether the time entry is still running. Toggl marks
+// running entries with a negative duration.
+func (te *TimeEntry) IsRunning() bool {
+	return te.Duration < 0
+}
+
+// Elapsed returns the tracked duration of the time entry. For a running
+// entry it is the time elapsed between its start and now.
+func (te *TimeEntry) Elapsed(now time.Time) time.Duration {
+	if !te.IsRunning() {
+		return time.Duration(te.Duration) * time.Second
+	}
+	if te.Start != nil {
+		return now.Sub(*te.Start)
+	}
+	// The duration of a running entry is the negated start epoch.
+	return time.Duration(now.Unix()+int64(te.Duration)) * time.Second
 }
 
 // TimeEntryResponse acts as a response wrapper where response returns
@@ -42,6 +115,12 @@ type TimeEntryResponse struct {
 	Data *TimeEntry `json:"data,omitempty"`
 }
 
+// TimeEntryMassResponse acts as a response wrapper where response returns
+// in format of "data": [ ... ].
+type TimeEntryMassResponse struct {
+	Data []TimeEntry `json:"data,omitempty"`
+}
+
 // TimeEntryCreate represents posted data to be sent to time entries endpoint.
 type TimeEntryCreate struct {
 	TimeEntry *TimeEntry `json:"time_entry,omitempty"`
@@ -51,9 +130,14 @@ type TimeEntryCreate struct {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/time_entries.md#create-a-time-entry
 func (s *TimeEntriesService) Create(te *TimeEntry) (*TimeEntry, error) {
+	return s.CreateContext(context.Background(), te)
+}
+
+// CreateContext is like Create, but with the provided context.
+func (s *TimeEntriesService) CreateContext(ctx context.Context, te *TimeEntry) (*TimeEntry, error) {
 	u := "time_entries"
 	tec := &TimeEntryCreate{te}
-	req, err := s.client.NewRequest("POST", u, tec)
+	req, err := s.client.NewRequestContext(ctx, "POST", u, tec)
 	if err != nil {
 		return nil, err
 	}
@@ -68,9 +152,14 @@ func (s *TimeEntriesService) Create(te *TimeEntry) (*TimeEntry, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/time_entries.md#start-a-time-entry
 func (s *TimeEntriesService) Start(te *TimeEntry) (*TimeEntry, error) {
+	return s.StartContext(context.Background(), te)
+}
+
+// StartContext is like Start, but with the provided context.
+func (s *TimeEntriesService) StartContext(ctx context.Context, te *TimeEntry) (*TimeEntry, error) {
 	u := "time_entries/start"
 	tec := &TimeEntryCreate{te}
-	req, err := s.client.NewRequest("POST", u, tec)
+	req, err := s.client.NewRequestContext(ctx, "POST", u, tec)
 	if err != nil {
 		return nil, err
 	}
@@ -85,9 +174,36 @@ func (s *TimeEntriesService) Start(te *TimeEntry) (*TimeEntry, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/time_entries.md#stop-a-time-entry
 func (s *TimeEntriesService) Stop(id int) (*TimeEntry, error) {
+	return s.StopContext(context.Background(), id)
+}
+
+// StopContext is like Stop, but with the provided context.
+func (s *TimeEntriesService) StopContext(ctx context.Context, id int) (*TimeEntry, error) {
 	u := fmt.Sprintf("time_entries/%v/stop", id)
 
-	req, err := s.client.NewRequest("PUT", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "PUT", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data := new(TimeEntryResponse)
+	_, err = s.client.Do(req, data)
+
+	return data.Data, err
+}
+
+// Current returns the running time entry, or nil if no time entry is
+// running.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/time_entries.md#get-running-time-entry
+func (s *TimeEntriesService) Current() (*TimeEntry, error) {
+	return s.CurrentContext(context.Background())
+}
+
+// CurrentContext is like Current, but with the provided context.
+func (s *TimeEntriesService) CurrentContext(ctx context.Context) (*TimeEntry, error) {
+	u := "time_entries/current"
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -102,8 +218,13 @@ func (s *TimeEntriesService) Stop(id int) (*TimeEntry, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/time_entries.md#get-time-entry-details
 func (s *TimeEntriesService) Get(id int) (*TimeEntry, error) {
+	return s.GetContext(context.Background(), id)
+}
+
+// GetContext is like Get, but with the provided context.
+func (s *TimeEntriesService) GetContext(ctx context.Context, id int) (*TimeEntry, error) {
 	u := fmt.Sprintf("time_entries/%v", id)
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -118,6 +239,11 @@ func (s *TimeEntriesService) Get(id int) (*TimeEntry, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/time_entries.md#update-a-time-entry
 func (s *TimeEntriesService) Update(te *TimeEntry) (*TimeEntry, error) {
+	return s.UpdateContext(context.Background(), te)
+}
+
+// UpdateContext is like Update, but with the provided context.
+func (s *TimeEntriesService) UpdateContext(ctx context.Context, te *TimeEntry) (*TimeEntry, error) {
 	if te == nil {
 		return nil, errors.New("TimeEntry cannot be nil")
 	}
@@ -128,7 +254,7 @@ func (s *TimeEntriesService) Update(te *TimeEntry) (*TimeEntry, error) {
 	u := fmt.Sprintf("time_entries/%v", te.ID)
 
 	tec := &TimeEntryCreate{te}
-	req, err := s.client.NewRequest("PUT", u, tec)
+	req, err := s.client.NewRequestContext(ctx, "PUT", u, tec)
 	if err != nil {
 		return nil, err
 	}
@@ -139,12 +265,66 @@ func (s *TimeEntriesService) Update(te *TimeEntry) (*TimeEntry, error) {
 	return data.Data, err
 }
 
+// MassUpdate updates multiple time entries at once, e.g. to add tags to
+// them:
+//
+//	te := &toggl.TimeEntry{Tags: []string{"billed"}, TagAction: toggl.TagActionAdd}
+//	entries, err := c.TimeEntries.MassUpdate([]int{1, 2, 3}, te)
+//
+// More than MaxIDsPerRequest ids are updated in several requests. If one
+// of them fails, the entries updated so far are returned with the error.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/time_entries.md#bulk-update-time-entries-tags
+func (s *TimeEntriesService) MassUpdate(ids []int, te *TimeEntry) ([]TimeEntry, error) {
+	return s.MassUpdateContext(context.Background(), ids, te)
+}
+
+// MassUpdateContext is like MassUpdate, but with the provided context.
+func (s *TimeEntriesService) MassUpdateContext(ctx context.Context, ids []int, te *TimeEntry) ([]TimeEntry, error) {
+	if te == nil {
+		return nil, errors.New("TimeEntry cannot be nil")
+	}
+	if err := validateIDs(ids); err != nil {
+		return nil, err
+	}
+
+	var entries []TimeEntry
+	for _, chunk := range chunkIDs(ids) {
+		data, err := s.massUpdate(ctx, chunk, te)
+		entries = append(entries, data...)
+		if err != nil {
+			return entries, err
+		}
+	}
+	return entries, nil
+}
+
+func (s *TimeEntriesService) massUpdate(ctx context.Context, ids []int, te *TimeEntry) ([]TimeEntry, error) {
+	u := fmt.Sprintf("time_entries/%v", joinIDs(ids))
+
+	tec := &TimeEntryCreate{te}
+	req, err := s.client.NewRequestContext(ctx, "PUT", u, tec)
+	if err != nil {
+		return nil, err
+	}
+
+	data := new(TimeEntryMassResponse)
+	_, err = s.client.Do(req, data)
+
+	return data.Data, err
+}
+
 // Delete a time entry.
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/time_entries.md#delete-a-time-entry
 func (s *TimeEntriesService) Delete(id int) error {
+	return s.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is like Delete, but with the provided context.
+func (s *TimeEntriesService) DeleteContext(ctx context.Context, id int) error {
 	u := fmt.Sprintf("time_entries/%v", id)
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, nil)
 	if err != nil {
 		return err
 	}
@@ -153,13 +333,23 @@ func (s *TimeEntriesService) Delete(id int) error {
 	return err
 }
 
+// TimeEntriesListLimit is the largest number of time entries returned by
+// a single List request. Use ListIter to get all entries of a range.
+const TimeEntriesListLimit = 1000
+
 // List time entries. With start and end parameters you can specify
 // the date range of the time entries returned. If start and end
 // are not specified, time entries started during the last 9 days
 // are returned. start and end must be ISO 8601 date and time strings.
+// At most TimeEntriesListLimit time entries are returned.
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/time_entries.md#get-time-entries-started-in-a-specific-time-range
 func (s *TimeEntriesService) List(start, end *time.Time) ([]TimeEntry, error) {
+	return s.ListContext(context.Background(), start, end)
+}
+
+// ListContext is like List, but with the provided context.
+func (s *TimeEntriesService) ListContext(ctx context.Context, start, end *time.Time) ([]TimeEntry, error) {
 	u := "time_entries"
 	params := url.Values{}
 	if start != nil {
@@ -170,7 +360,7 @@ func (s *TimeEntriesService) List(start, end *time.Time) ([]TimeEntry, error) {
 	}
 	u += "?" + params.Encode()
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -180,3 +370,101 @@ func (s *TimeEntriesService) List(start, end *time.Time) ([]TimeEntry, error) {
 
 	return *data, err
 }
+
+// TimeEntryIterator walks all time entries started in a time range,
+// issuing as many List requests as needed to get past
+// TimeEntriesListLimit:
+//
+//	it := c.TimeEntries.ListIter(start, end)
+//	for it.Next() {
+//		te := it.Entry()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Stopping before Next returns false leaves the remaining entries
+// unfetched.
+type TimeEntryIterator struct {
+	ctx     context.Context
+	s       *TimeEntriesService
+	start   time.Time
+	end     time.Time
+	seen    map[int]bool
+	entries []TimeEntry
+	entry   TimeEntry
+	done    bool
+	err     error
+}
+
+// ListIter returns an iterator over the time entries started between
+// start and end.
+func (s *TimeEntriesService) ListIter(start, end time.Time) *TimeEntryIterator {
+	return s.ListIterContext(context.Background(), start, end)
+}
+
+// ListIterContext is like ListIter, but with the provided context.
+func (s *TimeEntriesService) ListIterContext(ctx context.Context, start, end time.Time) *TimeEntryIterator {
+	return &TimeEntryIterator{
+		ctx:   ctx,
+		s:     s,
+		start: start,
+		end:   end,
+		seen:  make(map[int]bool),
+	}
+}
+
+// Next advances to the next time entry, which is then available through
+// Entry. It returns false when there are no more entries or an error
+// occurred.
+func (it *TimeEntryIterator) Next() bool {
+	for len(it.entries) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.fetch()
+	}
+	it.entry, it.entries = it.entries[0], it.entries[1:]
+	return true
+}
+
+// fetch lists the entries from it.start on. Entries are returned in order
+// of their start, so a full page is followed by a request starting at the
+// last entry's start. Entries sharing that start are returned twice and
+// skipped the second time.
+func (it *TimeEntryIterator) fetch() {
+	page, err := it.s.ListContext(it.ctx, &it.start, &it.end)
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	for _, te := range page {
+		if !it.seen[te.ID] {
+			it.seen[te.ID] = true
+			it.entries = append(it.entries, te)
+		}
+	}
+
+	if len(page) < TimeEntriesListLimit || len(it.entries) == 0 {
+		it.done = true
+		return
+	}
+	last := page[len(page)-1]
+	if last.Start == nil {
+		it.done = true
+		return
+	}
+	it.start = *last.Start
+}
+
+// Entry returns the current time entry.
+func (it *TimeEntryIterator) Entry() TimeEntry {
+	return it.entry
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *TimeEntryIterator) Err() error {
+	return it.err
+}
@@ -6,10 +6,12 @@
 package toggl
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -43,6 +45,57 @@ func TestTimeEntriesService_Create(t *testing.T) {
 	}
 }
 
+func TestTimeEntriesService_Create_fields(t *testing.T) {
+	setup()
+	defer teardown()
+
+	input := &TimeEntry{Description: "desc", GUID: "guid", UserID: 2}
+
+	mux.HandleFunc("/time_entries", func(w http.ResponseWriter, r *http.Request) {
+		v := new(TimeEntryCreate)
+		json.NewDecoder(r.Body).Decode(v)
+
+		testMethod(t, r, "POST")
+		if !reflect.DeepEqual(v.TimeEntry, input) {
+			t.Errorf("Request body = %+v, want %+v", v, input)
+		}
+
+		fmt.Fprint(w, `{"data":{"id": 1, "description": "desc", "guid": "guid", "uid": 2, "at": "2013-03-06T12:18:42Z"}}`)
+	})
+
+	result, err := client.TimeEntries.Create(input)
+	if err != nil {
+		t.Errorf("TimeEntries.Create returned error: %v", err)
+	}
+
+	at := time.Date(2013, time.March, 6, 12, 18, 42, 0, time.UTC)
+	want := &TimeEntry{ID: 1, Description: "desc", GUID: "guid", UserID: 2, At: &at}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("TimeEntries.Create returned %v, want %v", result, want)
+	}
+}
+
+func TestTimeEntriesService_Get_deleted(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/time_entries/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data":{"id": 1, "guid": "guid", "server_deleted_at": "2013-03-06T12:18:42Z"}}`)
+	})
+
+	result, err := client.TimeEntries.Get(1)
+	if err != nil {
+		t.Errorf("TimeEntries.Get returned error: %v", err)
+	}
+
+	deleted := time.Date(2013, time.March, 6, 12, 18, 42, 0, time.UTC)
+	want := &TimeEntry{ID: 1, GUID: "guid", ServerDeletedAt: &deleted}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("TimeEntries.Get returned %v, want %v", result, want)
+	}
+}
+
 func TestTimeEntriesService_Start(t *testing.T) {
 	setup()
 	defer teardown()
@@ -92,6 +145,43 @@ func TestTimeEntriesService_Stop(t *testing.T) {
 	}
 }
 
+func TestTimeEntriesService_Current(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/time_entries/current", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"data":{"id": 1, "duration": -1361879938}}`)
+	})
+
+	result, err := client.TimeEntries.Current()
+	if err != nil {
+		t.Errorf("TimeEntries.Current returned error: %v", err)
+	}
+
+	want := &TimeEntry{ID: 1, Duration: -1361879938}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("TimeEntries.Current returned %v, want %v", result, want)
+	}
+}
+
+func TestTimeEntriesService_Current_notRunning(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/time_entries/current", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":null}`)
+	})
+
+	result, err := client.TimeEntries.Current()
+	if err != nil {
+		t.Errorf("TimeEntries.Current returned error: %v", err)
+	}
+	if result != nil {
+		t.Errorf("TimeEntries.Current returned %v, want nil", result)
+	}
+}
+
 func TestTimeEntriesService_Get(t *testing.T) {
 	setup()
 	defer teardown()
@@ -141,6 +231,50 @@ func TestTimeEntriesService_Update(t *testing.T) {
 	}
 }
 
+func TestTimeEntriesService_MassUpdate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	input := &TimeEntry{Tags: []string{"billed"}, TagAction: TagActionAdd}
+
+	mux.HandleFunc("/time_entries/1,2", func(w http.ResponseWriter, r *http.Request) {
+		v := new(TimeEntryCreate)
+		json.NewDecoder(r.Body).Decode(v)
+
+		testMethod(t, r, "PUT")
+		if !reflect.DeepEqual(v.TimeEntry, input) {
+			t.Errorf("Request body = %+v, want %+v", v.TimeEntry, input)
+		}
+
+		fmt.Fprint(w, `{"data":[{"id": 1, "tags": ["billed"]},{"id": 2, "tags": ["dev", "billed"]}]}`)
+	})
+
+	result, err := client.TimeEntries.MassUpdate([]int{1, 2}, input)
+	if err != nil {
+		t.Errorf("TimeEntries.MassUpdate returned error: %v", err)
+	}
+
+	want := []TimeEntry{
+		{ID: 1, Tags: []string{"billed"}},
+		{ID: 2, Tags: []string{"dev", "billed"}},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("TimeEntries.MassUpdate returned %v, want %v", result, want)
+	}
+}
+
+func TestTimeEntriesService_MassUpdate_invalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	if _, err := client.TimeEntries.MassUpdate(nil, &TimeEntry{}); err == nil {
+		t.Errorf("TimeEntries.MassUpdate returned no error for empty ids")
+	}
+	if _, err := client.TimeEntries.MassUpdate([]int{1}, nil); err == nil {
+		t.Errorf("TimeEntries.MassUpdate returned no error for nil TimeEntry")
+	}
+}
+
 func TestTimeEntriesService_Delete(t *testing.T) {
 	setup()
 	defer teardown()
@@ -181,3 +315,213 @@ func TestTimeEntriesService_List(t *testing.T) {
 		t.Errorf("TimeEntries.List returned %v, want %v", result, want)
 	}
 }
+
+func TestTimeEntriesService_ListContext_canceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/time_entries", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := client.TimeEntries.ListContext(ctx, nil, nil)
+	if err != context.Canceled {
+		t.Errorf("TimeEntries.ListContext returned error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestTimeEntry_JSON(t *testing.T) {
+	start := time.Date(2013, time.March, 5, 7, 58, 58, 0, time.UTC)
+	stop := time.Date(2013, time.March, 5, 8, 58, 58, 0, time.UTC)
+	at := time.Date(2013, time.March, 6, 12, 18, 42, 0, time.UTC)
+
+	input := `{
+		"id": 436694100,
+		"guid": "6a6b6a6b-7c7c-4d4d-8e8e-9f9f9f9f9f9f",
+		"wid": 777,
+		"pid": 193791,
+		"tid": 1894675,
+		"uid": 123,
+		"description": "Meeting with possible clients",
+		"billable": true,
+		"start": "2013-03-05T07:58:58Z",
+		"stop": "2013-03-05T08:58:58Z",
+		"duration": 3600,
+		"created_with": "go-toggl",
+		"tags": ["billed"],
+		"duronly": true,
+		"at": "2013-03-06T12:18:42Z",
+		"server_deleted_at": "2013-03-06T12:18:42Z"
+	}`
+
+	want := &TimeEntry{
+		ID:              436694100,
+		GUID:            "6a6b6a6b-7c7c-4d4d-8e8e-9f9f9f9f9f9f",
+		WorkspaceID:     777,
+		ProjectID:       193791,
+		TaskID:          1894675,
+		UserID:          123,
+		Description:     "Meeting with possible clients",
+		Billable:        true,
+		Start:           &start,
+		Stop:            &stop,
+		Duration:        3600,
+		CreatedWith:     "go-toggl",
+		Tags:            []string{"billed"},
+		Duronly:         true,
+		At:              &at,
+		ServerDeletedAt: &at,
+	}
+
+	te := new(TimeEntry)
+	if err := json.Unmarshal([]byte(input), te); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	if !reflect.DeepEqual(te, want) {
+		t.Errorf("json.Unmarshal = %+v, want %+v", te, want)
+	}
+
+	b, err := json.Marshal(te)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	roundTrip := new(TimeEntry)
+	if err := json.Unmarshal(b, roundTrip); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	if !reflect.DeepEqual(roundTrip, want) {
+		t.Errorf("JSON round trip = %+v, want %+v", roundTrip, want)
+	}
+}
+
+func TestTimeEntry_Elapsed(t *testing.T) {
+	start := time.Date(2013, time.March, 5, 7, 58, 58, 0, time.UTC)
+	now := start.Add(90 * time.Minute)
+
+	tests := []struct {
+		te      *TimeEntry
+		running bool
+		want    time.Duration
+	}{
+		{&TimeEntry{Duration: 3600}, false, time.Hour},
+		{&TimeEntry{Start: &start, Duration: int(-start.Unix())}, true, 90 * time.Minute},
+		{&TimeEntry{Duration: int(-start.Unix())}, true, 90 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := tt.te.IsRunning(); got != tt.running {
+			t.Errorf("IsRunning() of %+v = %v, want %v", tt.te, got, tt.running)
+		}
+		if got := tt.te.Elapsed(now); got != tt.want {
+			t.Errorf("Elapsed() of %+v = %v, want %v", tt.te, got, tt.want)
+		}
+	}
+}
+
+func TestNewTimeEntry(t *testing.T) {
+	start := time.Date(2013, time.March, 5, 7, 58, 58, 0, time.UTC)
+	stop := start.Add(90 * time.Minute)
+
+	te, err := NewTimeEntry(start, 90*time.Minute+500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewTimeEntry returned error: %v", err)
+	}
+
+	want := &TimeEntry{Start: &start, Stop: &stop, Duration: 5400}
+	if !reflect.DeepEqual(te, want) {
+		t.Errorf("NewTimeEntry returned %+v, want %+v", te, want)
+	}
+	if te.IsRunning() {
+		t.Errorf("IsRunning() of NewTimeEntry = true, want false")
+	}
+	if got := te.Elapsed(time.Now()); got != 90*time.Minute {
+		t.Errorf("Elapsed() of NewTimeEntry = %v, want %v", got, 90*time.Minute)
+	}
+
+	for _, d := range []time.Duration{0, 500 * time.Millisecond, -time.Hour} {
+		if _, err := NewTimeEntry(start, d); err == nil {
+			t.Errorf("NewTimeEntry(%v) returned no error", d)
+		}
+	}
+}
+
+func TestNewDurationOnlyTimeEntry(t *testing.T) {
+	start := time.Date(2013, time.March, 5, 7, 58, 58, 0, time.UTC)
+
+	te, err := NewDurationOnlyTimeEntry(start, time.Hour)
+	if err != nil {
+		t.Fatalf("NewDurationOnlyTimeEntry returned error: %v", err)
+	}
+	if !te.Duronly || te.Duration != 3600 {
+		t.Errorf("NewDurationOnlyTimeEntry returned %+v, want duronly entry of 3600s", te)
+	}
+}
+
+func TestNewRunningTimeEntry(t *testing.T) {
+	start := time.Date(2013, time.March, 5, 7, 58, 58, 0, time.UTC)
+
+	te := NewRunningTimeEntry(start)
+	want := &TimeEntry{Start: &start, Duration: -1362470338}
+	if !reflect.DeepEqual(te, want) {
+		t.Errorf("NewRunningTimeEntry returned %+v, want %+v", te, want)
+	}
+	if !te.IsRunning() {
+		t.Errorf("IsRunning() of NewRunningTimeEntry = false, want true")
+	}
+	if got := te.Elapsed(start.Add(time.Minute)); got != time.Minute {
+		t.Errorf("Elapsed() of NewRunningTimeEntry = %v, want %v", got, time.Minute)
+	}
+}
+
+func TestTimeEntriesService_ListIter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	start := time.Date(2013, time.July, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2013, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	// A full first page whose last two entries start at the same time,
+	// followed by a page repeating the last of them.
+	var first []string
+	for i := 1; i <= TimeEntriesListLimit; i++ {
+		s := start.Add(time.Duration(i) * time.Minute)
+		if i == TimeEntriesListLimit {
+			s = s.Add(-time.Minute)
+		}
+		first = append(first, fmt.Sprintf(`{"id": %d, "start": "%s"}`, i, s.Format(time.RFC3339)))
+	}
+	last := start.Add(time.Duration(TimeEntriesListLimit-1) * time.Minute)
+
+	var starts []string
+	mux.HandleFunc("/time_entries", func(w http.ResponseWriter, r *http.Request) {
+		starts = append(starts, r.FormValue("start_date"))
+		testFormValues(t, r, values{"end_date": end.Format(time.RFC3339)})
+		switch r.FormValue("start_date") {
+		case start.Format(time.RFC3339):
+			fmt.Fprint(w, "["+strings.Join(first, ",")+"]")
+		case last.Format(time.RFC3339):
+			fmt.Fprintf(w, `[{"id": %d, "start": "%s"}, {"id": 2000}]`, TimeEntriesListLimit, last.Format(time.RFC3339))
+		}
+	})
+
+	it := client.TimeEntries.ListIter(start, end)
+	n := 0
+	var lastID int
+	for it.Next() {
+		n++
+		lastID = it.Entry().ID
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("TimeEntryIterator returned error: %v", err)
+	}
+
+	if n != TimeEntriesListLimit+1 || lastID != 2000 {
+		t.Errorf("TimeEntryIterator returned %d entries ending with %d, want %d ending with 2000", n, lastID, TimeEntriesListLimit+1)
+	}
+	if want := []string{start.Format(time.RFC3339), last.Format(time.RFC3339)}; !reflect.DeepEqual(starts, want) {
+		t.Errorf("TimeEntryIterator requested start dates %v, want %v", starts, want)
+	}
+}
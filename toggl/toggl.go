@@ -11,6 +11,28 @@ Client (which requires api token string on the first parameter):
 
 	c := toggl.NewClient("YOUR_API_TOKEN")
 
+NewClient accepts options to customize the client, for example:
+
+	c := toggl.NewClient("YOUR_API_TOKEN",
+		toggl.WithHTTPClient(&http.Client{Timeout: 30 * time.Second}),
+		toggl.WithUserAgent("my-app/1.0"),
+	)
+
+Other credentials can be used through an Authenticator. Signing in with
+email and password opens a session, whose cookie then authenticates the
+client and whose user carries the API token:
+
+	c := toggl.NewClient("", toggl.WithAuthenticator(toggl.BasicAuth{
+		Username: "user@example.com",
+		Password: "secret",
+	}))
+	s, err := c.Sessions.Create()
+	if err != nil {
+		return err
+	}
+	c.SetAuthenticator(toggl.SessionAuth{Cookie: s.Cookie})
+	token := s.User.APIToken
+
 With client object set, you can call Toggl endpoints:
 
 	// Get list of workspaces
@@ -23,6 +45,31 @@ With client object set, you can call Toggl endpoints:
 		fmt.Println(w.ID, w.Name)
   }
 
+Every service method has a Context variant which accepts a context.Context
+for cancellation and deadlines:
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	entries, err := c.TimeEntries.ListContext(ctx, nil, nil)
+
+Throttled (429) and failed (5xx) requests are not retried unless the Client
+has a RetryPolicy. A RateLimiter keeps bulk operations within Toggl's limits:
+
+	c := toggl.NewClient("YOUR_API_TOKEN",
+		toggl.WithRetryPolicy(&toggl.RetryPolicy{MaxAttempts: 5}),
+		toggl.WithRateLimiter(toggl.NewRateLimiter(1, 1)),
+	)
+
+Reports are fetched from the Toggl Reports API v2 through the Reports service:
+
+	r, err := c.Reports.Summary(&toggl.ReportOptions{
+		WorkspaceID: 123,
+		Since:       time.Date(2013, time.March, 1, 0, 0, 0, 0, time.UTC),
+		Until:       time.Date(2013, time.March, 31, 0, 0, 0, 0, time.UTC),
+		Grouping:    toggl.GroupByClients,
+		Subgrouping: toggl.GroupByProjects,
+	})
+
 The full Toggl API is documented at https://github.com/toggl/toggl_api_docs/.
 */
 
@@ -30,12 +77,15 @@ package toggl
 
 import (
 	"bytes"
-	"encoding/base64"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 const (
@@ -45,6 +95,9 @@ const (
 	// BaseURL represents Toggl API base URL
 	BaseURL = "https://toggl.com/api/v8/"
 
+	// ReportsBaseURL represents Toggl Reports API base URL
+	ReportsBaseURL = "https://toggl.com/reports/api/v2/"
+
 	// UserAgent represents this client User-Agent
 	UserAgent = "go-toggl/" + LibraryVersion
 )
@@ -54,19 +107,31 @@ type Client struct {
 	// HTTP client used to communicate with the API
 	client *http.Client
 
-	// base64 encoded authorization header.
-	basicAuth string
+	// Credentials added to every request.
+	auth Authenticator
 
 	// Base URL for API requests.
 	BaseURL *url.URL
 
+	// Base URL for Reports API requests.
+	ReportsURL *url.URL
+
 	// UserAgent agent used when communicating with Toggl API.
 	UserAgent string
 
+	// RetryPolicy controls how throttled and failed requests are retried.
+	// Requests are not retried when it is nil.
+	RetryPolicy *RetryPolicy
+
+	// RateLimiter, if set, is waited on before each request is sent.
+	RateLimiter *RateLimiter
+
 	// Services used for talking to differents parts of the API.
 	Clients        *ClientsService
 	Projects       *ProjectsService
 	ProjectUsers   *ProjectUsersService
+	Reports        *ReportsService
+	Sessions       *SessionsService
 	Tags           *TagsService
 	Tasks          *TasksService
 	TimeEntries    *TimeEntriesService
@@ -77,20 +142,25 @@ type Client struct {
 
 // NewClient returns a new Toggl API client. Expects user's api token
 // to be provided. Api token can be found in https://www.toggl.com/user/edit
-func NewClient(apiToken string) *Client {
+//
+// Without options the client talks to BaseURL using http.DefaultClient.
+func NewClient(apiToken string, opts ...ClientOption) *Client {
 	baseURL, _ := url.Parse(BaseURL)
-	basicAuth := base64.StdEncoding.EncodeToString([]byte(apiToken + ":api_token"))
+	reportsURL, _ := url.Parse(ReportsBaseURL)
 	client := http.DefaultClient
 
 	c := &Client{
-		client:    client,
-		basicAuth: basicAuth,
-		BaseURL:   baseURL,
-		UserAgent: UserAgent,
+		client:     client,
+		auth:       APITokenAuth{Token: apiToken},
+		BaseURL:    baseURL,
+		ReportsURL: reportsURL,
+		UserAgent:  UserAgent,
 	}
 	c.Clients = &ClientsService{client: c}
 	c.Projects = &ProjectsService{client: c}
 	c.ProjectUsers = &ProjectUsersService{client: c}
+	c.Reports = &ReportsService{client: c}
+	c.Sessions = &SessionsService{client: c}
 	c.Tags = &TagsService{client: c}
 	c.Tasks = &TasksService{client: c}
 	c.TimeEntries = &TimeEntriesService{client: c}
@@ -98,15 +168,34 @@ func NewClient(apiToken string) *Client {
 	c.Workspaces = &WorkspacesService{client: c}
 	c.WorkspaceUsers = &WorkspaceUsersService{client: c}
 
+	for _, opt := range opts {
+		opt(c)
+	}
+
 	return c
 }
 
+// SetAuthenticator replaces the credentials added to subsequent requests,
+// e.g. with a SessionAuth after logging in. It must not be called
+// concurrently with requests made by c. A nil a is ignored.
+func (c *Client) SetAuthenticator(a Authenticator) {
+	if a != nil {
+		c.auth = a
+	}
+}
+
 // NewRequest creates an API request. A relative URL can be provided in urlStr,
 // in which case it is resolved relative to the BaseURL of the Client.
 // Relative URLs should always be specified without a preceding slash.
 // If specified, the value pointed to by body is JSON encoded and included
 // as the request body.
 func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Request, error) {
+	return c.NewRequestContext(context.Background(), method, urlStr, body)
+}
+
+// NewRequestContext is like NewRequest, but the returned request carries
+// ctx so that it can be canceled or given a deadline.
+func (c *Client) NewRequestContext(ctx context.Context, method, urlStr string, body interface{}) (*http.Request, error) {
 	ref, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, err
@@ -122,48 +211,181 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 		}
 	}
 
-	req, err := http.NewRequest(method, u.String(), buf)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), buf)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Add("User-Agent", c.UserAgent)
-	req.Header.Add("Authorization", fmt.Sprintf("Basic %s", c.basicAuth))
+	if err := c.auth.Authenticate(req); err != nil {
+		return nil, err
+	}
 
 	return req, nil
 }
 
 // Do sends an API request and returns the API response. The API response is
 // decoded and stored in the value pointed by v, or returned as an error if
-// and API error has occurred.
+// and API error has occurred. If the request's context is canceled or its
+// deadline is exceeded, the context's error is returned.
+//
+// The request is retried according to the Client's RetryPolicy and throttled
+// by its RateLimiter, if any.
 func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
-	resp, err := c.client.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
-		return nil, err
+		return resp, err
 	}
 
 	defer resp.Body.Close()
 
-	err = CheckResponse(resp)
-	if err != nil {
-		return resp, err
-	}
-
 	if v != nil {
 		err = json.NewDecoder(resp.Body).Decode(v)
 	}
 	return resp, err
 }
 
+// DoRaw is like Do, but returns the response with its body unread instead
+// of decoding it, e.g. to stream a file. The caller must close the body
+// when the error is nil.
+func (c *Client) DoRaw(req *http.Request) (*http.Response, error) {
+	return c.send(req)
+}
+
+// send sends req, retrying it as allowed by the RetryPolicy, and returns
+// the first successful response with its body unread. API errors are
+// returned along with the response, whose body is already closed.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		if c.RateLimiter != nil {
+			if err := c.RateLimiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			// The context error is more useful to callers than the
+			// *url.Error wrapping it.
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+		} else if err = CheckResponse(resp); err == nil {
+			return resp, nil
+		} else {
+			resp.Body.Close()
+		}
+
+		if !c.RetryPolicy.shouldRetry(req, resp, attempt) {
+			return resp, err
+		}
+		next, rerr := rewindRequest(req)
+		if rerr != nil {
+			return resp, err
+		}
+
+		t := time.NewTimer(c.RetryPolicy.backoff(resp, attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+		req = next
+	}
+}
+
+// DoContext is like Do, but sends req with the provided context.
+func (c *Client) DoContext(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+	return c.Do(req.WithContext(ctx), v)
+}
+
+// ErrorResponse reports an error caused by an API request.
+type ErrorResponse struct {
+	// HTTP response that caused this error
+	Response *http.Response
+
+	// HTTP status code of the response
+	StatusCode int
+
+	// Raw response body
+	Body []byte
+
+	// Error messages decoded from the response body, if any
+	Messages []string
+}
+
+func (r *ErrorResponse) Error() string {
+	if r.Response == nil || r.Response.Request == nil {
+		return fmt.Sprintf("%d %v", r.StatusCode, string(r.Body))
+	}
+	return fmt.Sprintf("%v %v: %d %v",
+		r.Response.Request.Method, r.Response.Request.URL, r.StatusCode, string(r.Body))
+}
+
 // CheckResponse checks the API response for error, and returns the error
 // if present. A response is considered an error if it has a status code outside
-// the 200 range.
+// the 200 range. API error responses are returned as *ErrorResponse.
 func CheckResponse(r *http.Response) error {
 	if c := r.StatusCode; 200 <= c && c <= 299 {
 		return nil
 	}
 
-	message, _ := ioutil.ReadAll(r.Body)
+	body, _ := ioutil.ReadAll(r.Body)
+
+	return &ErrorResponse{
+		Response:   r,
+		StatusCode: r.StatusCode,
+		Body:       body,
+		Messages:   errorMessages(body),
+	}
+}
+
+// errorMessages decodes error messages from an API error body. Toggl
+// reports errors either as a JSON array of strings, a JSON string or
+// plain text.
+func errorMessages(body []byte) []string {
+	var messages []string
+	if err := json.Unmarshal(body, &messages); err == nil {
+		return messages
+	}
+
+	var message string
+	if err := json.Unmarshal(body, &message); err == nil {
+		return []string{message}
+	}
+
+	if message = strings.TrimSpace(string(body)); message != "" {
+		return []string{message}
+	}
+	return nil
+}
+
+// statusCode returns the status code of the *ErrorResponse in err's chain,
+// or 0 if there is none.
+func statusCode(err error) int {
+	var e *ErrorResponse
+	if errors.As(err, &e) {
+		return e.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is an API error for a missing resource.
+func IsNotFound(err error) bool {
+	return statusCode(err) == http.StatusNotFound
+}
+
+// IsUnauthorized reports whether err is an API error caused by missing or
+// invalid credentials. Toggl responds with 403 to a bad API token.
+func IsUnauthorized(err error) bool {
+	c := statusCode(err)
+	return c == http.StatusUnauthorized || c == http.StatusForbidden
+}
 
-	return fmt.Errorf("%v %v: %d %v", r.Request.Method, r.Request.URL, r.StatusCode, string(message))
+// IsRateLimited reports whether err is an API error caused by Toggl
+// throttling the client.
+func IsRateLimited(err error) bool {
+	return statusCode(err) == http.StatusTooManyRequests
 }
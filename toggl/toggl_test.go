@@ -6,10 +6,16 @@
 package toggl
 
 import (
+	"context"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 var (
@@ -32,8 +38,9 @@ func setup() {
 	server = httptest.NewServer(mux)
 
 	// toggl client configured to use test server
-	client = NewClient("")
-	client.BaseURL, _ = url.Parse(server.URL)
+	u, _ := url.Parse(server.URL)
+	ru, _ := url.Parse(server.URL + "/reports/api/v2/")
+	client = NewClient("", WithBaseURL(u), WithReportsBaseURL(ru))
 }
 
 // teardown closes the test HTTP server.
@@ -56,3 +63,155 @@ func testFormValues(t *testing.T, r *http.Request, values values) {
 		}
 	}
 }
+
+func TestNewRequestContext(t *testing.T) {
+	c := NewClient("")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := c.NewRequestContext(ctx, "GET", "me", nil)
+	if err != nil {
+		t.Fatalf("NewRequestContext returned error: %v", err)
+	}
+
+	if req.Context() != ctx {
+		t.Errorf("NewRequestContext request context = %v, want %v", req.Context(), ctx)
+	}
+	if want := BaseURL + "me"; req.URL.String() != want {
+		t.Errorf("NewRequestContext URL = %v, want %v", req.URL, want)
+	}
+}
+
+func TestDo_canceledContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	called := false
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, _ := client.NewRequestContext(ctx, "GET", "me", nil)
+	_, err := client.Do(req, nil)
+	if err != context.Canceled {
+		t.Errorf("Do returned error %v, want %v", err, context.Canceled)
+	}
+	if called {
+		t.Errorf("Do sent a request with a canceled context")
+	}
+}
+
+func TestDoContext_deadlineExceeded(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	req, _ := client.NewRequest("GET", "me", nil)
+	_, err := client.DoContext(ctx, req, nil)
+	if err != context.DeadlineExceeded {
+		t.Errorf("DoContext returned error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestCheckResponse(t *testing.T) {
+	res := &http.Response{
+		Request:    &http.Request{Method: "GET", URL: &url.URL{Path: "clients/1"}},
+		StatusCode: http.StatusBadRequest,
+		Body:       ioutil.NopCloser(strings.NewReader(`["Name has already been taken"]`)),
+	}
+
+	err, ok := CheckResponse(res).(*ErrorResponse)
+	if !ok {
+		t.Fatalf("CheckResponse returned %T, want *ErrorResponse", err)
+	}
+
+	want := &ErrorResponse{
+		Response:   res,
+		StatusCode: http.StatusBadRequest,
+		Body:       []byte(`["Name has already been taken"]`),
+		Messages:   []string{"Name has already been taken"},
+	}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("CheckResponse returned %+v, want %+v", err, want)
+	}
+	if got, want := err.Error(), `GET clients/1: 400 ["Name has already been taken"]`; got != want {
+		t.Errorf("ErrorResponse.Error() = %q, want %q", got, want)
+	}
+}
+
+func TestCheckResponse_plainText(t *testing.T) {
+	res := &http.Response{
+		Request:    &http.Request{},
+		StatusCode: http.StatusForbidden,
+		Body:       ioutil.NopCloser(strings.NewReader("Invalid API token\n")),
+	}
+
+	err := CheckResponse(res).(*ErrorResponse)
+	if want := []string{"Invalid API token"}; !reflect.DeepEqual(err.Messages, want) {
+		t.Errorf("ErrorResponse.Messages = %v, want %v", err.Messages, want)
+	}
+}
+
+func TestErrorResponse_noResponse(t *testing.T) {
+	err := &ErrorResponse{StatusCode: http.StatusNotFound, Body: []byte("Not Found")}
+	if got, want := err.Error(), "404 Not Found"; got != want {
+		t.Errorf("ErrorResponse.Error() = %q, want %q", got, want)
+	}
+}
+
+func TestDo_errorResponse(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/time_entries/1", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Not Found", http.StatusNotFound)
+	})
+
+	_, err := client.TimeEntries.Get(1)
+	if !IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+	if IsUnauthorized(err) || IsRateLimited(err) {
+		t.Errorf("IsUnauthorized or IsRateLimited(%v) = true, want false", err)
+	}
+}
+
+func TestErrorPredicates(t *testing.T) {
+	errorWithStatus := func(code int) error {
+		return fmt.Errorf("wrapped: %w", &ErrorResponse{StatusCode: code})
+	}
+
+	tests := []struct {
+		err                                 error
+		notFound, unauthorized, rateLimited bool
+	}{
+		{errorWithStatus(http.StatusNotFound), true, false, false},
+		{errorWithStatus(http.StatusUnauthorized), false, true, false},
+		{errorWithStatus(http.StatusForbidden), false, true, false},
+		{errorWithStatus(http.StatusTooManyRequests), false, false, true},
+		{errorWithStatus(http.StatusInternalServerError), false, false, false},
+		{fmt.Errorf("not an API error"), false, false, false},
+		{nil, false, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsNotFound(tt.err); got != tt.notFound {
+			t.Errorf("IsNotFound(%v) = %v, want %v", tt.err, got, tt.notFound)
+		}
+		if got := IsUnauthorized(tt.err); got != tt.unauthorized {
+			t.Errorf("IsUnauthorized(%v) = %v, want %v", tt.err, got, tt.unauthorized)
+		}
+		if got := IsRateLimited(tt.err); got != tt.rateLimited {
+			t.Errorf("IsRateLimited(%v) = %v, want %v", tt.err, got, tt.rateLimited)
+		}
+	}
+}
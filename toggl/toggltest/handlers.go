@@ -0,0 +1,857 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggltest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+// The serve methods serve the requests for a resource and return the
+// response body. s.mu is held while they run.
+
+// object looks up the single ID in p[1] with get.
+func object(p []string, get func(id int) bool) (int, error) {
+	ids, err := parseIDs(p[1])
+	if err != nil || len(ids) != 1 || !get(ids[0]) {
+		return 0, errNotFound
+	}
+	return ids[0], nil
+}
+
+// objects looks up the IDs in p[1] with get.
+func objects(p []string, get func(id int) bool) ([]int, error) {
+	ids, err := parseIDs(p[1])
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if !get(id) {
+			return nil, errNotFound
+		}
+	}
+	return ids, nil
+}
+
+// rawBody returns the object at key of r's JSON body.
+func rawBody(r *http.Request, key string) (json.RawMessage, error) {
+	var body map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, errorf(http.StatusBadRequest, "Invalid JSON: %v", err)
+	}
+	raw, ok := body[key]
+	if !ok || string(raw) == "null" {
+		return nil, errorf(http.StatusBadRequest, "Missing %s", key)
+	}
+	return raw, nil
+}
+
+// patch applies the raw JSON object to a copy of src, stored in dst.
+func patch(dst, src interface{}, raw json.RawMessage) error {
+	clone(dst, src)
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return errorf(http.StatusBadRequest, "Invalid JSON: %v", err)
+	}
+	return nil
+}
+
+// checkName validates the name of a new or renamed object.
+func checkName(name string, taken bool) error {
+	if strings.TrimSpace(name) == "" {
+		return errorf(http.StatusBadRequest, "Name can't be blank")
+	}
+	if taken {
+		return errorf(http.StatusBadRequest, "Name has already been taken")
+	}
+	return nil
+}
+
+func (s *Server) checkWorkspace(wid int) error {
+	if wid == 0 {
+		return errorf(http.StatusBadRequest, "Workspace ID is required")
+	}
+	if s.workspaces[wid] == nil {
+		return errorf(http.StatusBadRequest, "Workspace %d not found", wid)
+	}
+	return nil
+}
+
+func (s *Server) serveMe(r *http.Request, p []string) (interface{}, error) {
+	if len(p) != 1 || r.Method != "GET" {
+		return nil, errNotFound
+	}
+
+	// Only objects changed since the given Unix time are returned, along
+	// with the deleted ones. Tags have no timestamp and are always
+	// returned.
+	var since int64
+	if v := r.FormValue("since"); v != "" {
+		var err error
+		if since, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, errorf(http.StatusBadRequest, "Invalid since %q", v)
+		}
+	}
+	changed := func(at *time.Time) bool {
+		return since == 0 || at == nil || at.Unix() >= since
+	}
+
+	u := *s.users[s.userID]
+	if r.FormValue("with_related_data") == "true" {
+		u.Workspaces = s.listWorkspaces(func(w *toggl.Workspace) bool { return changed(w.At) })
+		u.Clients = s.listClients(func(c *toggl.WorkspaceClient) bool { return changed(c.At) })
+		u.Projects = s.listProjects(func(p *toggl.Project) bool { return changed(p.At) })
+		u.Tasks = s.listTasks(func(t *toggl.Task) bool { return changed(t.At) })
+		u.Tags = s.listTags(func(*toggl.Tag) bool { return true })
+		u.TimeEntries = s.listTimeEntries(func(te *toggl.TimeEntry) bool { return te.UserID == u.ID && changed(te.At) })
+
+		if since > 0 {
+			for _, c := range s.deleted.clients {
+				if changed(c.ServerDeletedAt) {
+					u.Clients = append(u.Clients, c)
+				}
+			}
+			for _, p := range s.deleted.projects {
+				if changed(p.ServerDeletedAt) {
+					u.Projects = append(u.Projects, p)
+				}
+			}
+			for _, t := range s.deleted.tasks {
+				if changed(t.ServerDeletedAt) {
+					u.Tasks = append(u.Tasks, t)
+				}
+			}
+			for _, t := range s.deleted.tags {
+				if changed(t.ServerDeletedAt) {
+					u.Tags = append(u.Tags, t)
+				}
+			}
+			for _, te := range s.deleted.timeEntries {
+				if te.UserID == u.ID && changed(te.ServerDeletedAt) {
+					u.TimeEntries = append(u.TimeEntries, te)
+				}
+			}
+		}
+	}
+	return &toggl.UserResponse{Since: int(s.now().Unix()), Data: &u}, nil
+}
+
+func (s *Server) serveWorkspaces(r *http.Request, p []string) (interface{}, error) {
+	if r.Method != "GET" {
+		return nil, errNotFound
+	}
+	if len(p) == 1 {
+		return s.listWorkspaces(func(*toggl.Workspace) bool { return true }), nil
+	}
+
+	wid, err := object(p, func(id int) bool { return s.workspaces[id] != nil })
+	if err != nil || len(p) != 3 {
+		return nil, errNotFound
+	}
+	switch p[2] {
+	case "users":
+		return s.listUsers(func(*toggl.User) bool { return true }), nil
+	case "clients":
+		return s.listClients(func(c *toggl.WorkspaceClient) bool { return c.WorkspaceID == wid }), nil
+	case "projects":
+		return s.listProjects(func(p *toggl.Project) bool { return p.WorkspaceID == wid }), nil
+	case "tasks":
+		return s.listTasks(func(t *toggl.Task) bool { return t.WorkspaceID == wid }), nil
+	case "tags":
+		return s.listTags(func(t *toggl.Tag) bool { return t.WorkspaceID == wid }), nil
+	}
+	return nil, errNotFound
+}
+
+func (s *Server) clientNameTaken(c *toggl.WorkspaceClient) bool {
+	for _, o := range s.clients {
+		if o.ID != c.ID && o.WorkspaceID == c.WorkspaceID && strings.EqualFold(o.Name, c.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) serveClients(r *http.Request, p []string) (interface{}, error) {
+	if len(p) == 1 {
+		switch r.Method {
+		case "GET":
+			return s.listClients(func(*toggl.WorkspaceClient) bool { return true }), nil
+		case "POST":
+			raw, err := rawBody(r, "client")
+			if err != nil {
+				return nil, err
+			}
+			c := new(toggl.WorkspaceClient)
+			if err := patch(c, c, raw); err != nil {
+				return nil, err
+			}
+			c.ID = 0
+			if err := s.checkWorkspace(c.WorkspaceID); err != nil {
+				return nil, err
+			}
+			if err := checkName(c.Name, s.clientNameTaken(c)); err != nil {
+				return nil, err
+			}
+			c.ID, c.At = s.nextID(), s.stamp()
+			s.clients[c.ID] = c
+			return data(c), nil
+		}
+		return nil, errNotFound
+	}
+
+	id, err := object(p, func(id int) bool { return s.clients[id] != nil })
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(p) == 3 && p[2] == "projects" && r.Method == "GET":
+		return s.listProjects(func(p *toggl.Project) bool { return p.ClientID == id }), nil
+	case len(p) != 2:
+		return nil, errNotFound
+	}
+
+	switch r.Method {
+	case "GET":
+		return data(s.clients[id]), nil
+	case "PUT":
+		raw, err := rawBody(r, "client")
+		if err != nil {
+			return nil, err
+		}
+		c := new(toggl.WorkspaceClient)
+		if err := patch(c, s.clients[id], raw); err != nil {
+			return nil, err
+		}
+		c.ID, c.WorkspaceID = id, s.clients[id].WorkspaceID
+		if err := checkName(c.Name, s.clientNameTaken(c)); err != nil {
+			return nil, err
+		}
+		c.At = s.stamp()
+		s.clients[id] = c
+		return data(c), nil
+	case "DELETE":
+		c := *s.clients[id]
+		c.At = s.stamp()
+		c.ServerDeletedAt = c.At
+		s.deleted.clients = append(s.deleted.clients, c)
+		delete(s.clients, id)
+		return nil, nil
+	}
+	return nil, errNotFound
+}
+
+func (s *Server) projectNameTaken(p *toggl.Project) bool {
+	for _, o := range s.projects {
+		if o.ID != p.ID && o.WorkspaceID == p.WorkspaceID && o.ClientID == p.ClientID && strings.EqualFold(o.Name, p.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) checkProject(p *toggl.Project) error {
+	if p.ClientID != 0 {
+		if c := s.clients[p.ClientID]; c == nil || c.WorkspaceID != p.WorkspaceID {
+			return errorf(http.StatusBadRequest, "Client %d not found", p.ClientID)
+		}
+	}
+	return checkName(p.Name, s.projectNameTaken(p))
+}
+
+func (s *Server) serveProjects(r *http.Request, p []string) (interface{}, error) {
+	if len(p) == 1 {
+		if r.Method != "POST" {
+			return nil, errNotFound
+		}
+		raw, err := rawBody(r, "project")
+		if err != nil {
+			return nil, err
+		}
+		pr := &toggl.Project{Active: true}
+		if err := patch(pr, pr, raw); err != nil {
+			return nil, err
+		}
+		pr.ID = 0
+		if err := s.checkWorkspace(pr.WorkspaceID); err != nil {
+			return nil, err
+		}
+		if err := s.checkProject(pr); err != nil {
+			return nil, err
+		}
+		pr.ID, pr.At = s.nextID(), s.stamp()
+		s.projects[pr.ID] = pr
+		return data(pr), nil
+	}
+
+	id, err := object(p, func(id int) bool { return s.projects[id] != nil })
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(p) == 3 && p[2] == "project_users" && r.Method == "GET":
+		return s.listProjectUsers(func(pu *toggl.ProjectUser) bool { return pu.ProjectID == id }), nil
+	case len(p) != 2:
+		return nil, errNotFound
+	}
+
+	switch r.Method {
+	case "GET":
+		return data(s.projects[id]), nil
+	case "PUT":
+		raw, err := rawBody(r, "project")
+		if err != nil {
+			return nil, err
+		}
+		pr := new(toggl.Project)
+		if err := patch(pr, s.projects[id], raw); err != nil {
+			return nil, err
+		}
+		pr.ID, pr.WorkspaceID = id, s.projects[id].WorkspaceID
+		if err := s.checkProject(pr); err != nil {
+			return nil, err
+		}
+		pr.At = s.stamp()
+		s.projects[id] = pr
+		return data(pr), nil
+	case "DELETE":
+		pr := *s.projects[id]
+		pr.At = s.stamp()
+		pr.ServerDeletedAt = pr.At
+		s.deleted.projects = append(s.deleted.projects, pr)
+		delete(s.projects, id)
+		return nil, nil
+	}
+	return nil, errNotFound
+}
+
+func (s *Server) serveProjectUsers(r *http.Request, p []string) (interface{}, error) {
+	if len(p) == 1 {
+		if r.Method != "POST" {
+			return nil, errNotFound
+		}
+		raw, err := rawBody(r, "project_user")
+		if err != nil {
+			return nil, err
+		}
+		// uid is a user ID, or a string of comma separated IDs to add
+		// several users at once.
+		var in struct {
+			toggl.ProjectUser
+			UID json.RawMessage `json:"uid"`
+		}
+		if err := json.Unmarshal(raw, &in); err != nil {
+			return nil, errorf(http.StatusBadRequest, "Invalid JSON: %v", err)
+		}
+		var uids string
+		mass := json.Unmarshal(in.UID, &uids) == nil
+		if !mass {
+			uids = string(in.UID)
+		}
+		ids, err := parseIDs(uids)
+		if err != nil {
+			return nil, errorf(http.StatusBadRequest, "Invalid user IDs %s", in.UID)
+		}
+
+		project := s.projects[in.ProjectID]
+		if project == nil {
+			return nil, errorf(http.StatusBadRequest, "Project %d not found", in.ProjectID)
+		}
+		for _, uid := range ids {
+			if s.users[uid] == nil {
+				return nil, errorf(http.StatusBadRequest, "User %d not found", uid)
+			}
+			for _, o := range s.projectUsers {
+				if o.ProjectID == project.ID && o.UserID == uid {
+					return nil, errorf(http.StatusBadRequest, "User %d has already been added to the project", uid)
+				}
+			}
+		}
+
+		var created []toggl.ProjectUser
+		for _, uid := range ids {
+			pu := in.ProjectUser
+			pu.ID, pu.UserID, pu.WorkspaceID, pu.At = s.nextID(), uid, project.WorkspaceID, s.stamp()
+			s.projectUsers[pu.ID] = &pu
+			created = append(created, pu)
+		}
+		if mass {
+			return data(created), nil
+		}
+		return data(created[0]), nil
+	}
+
+	ids, err := objects(p, func(id int) bool { return s.projectUsers[id] != nil })
+	if err != nil || len(p) != 2 {
+		return nil, errNotFound
+	}
+	switch r.Method {
+	case "PUT":
+		raw, err := rawBody(r, "project_user")
+		if err != nil {
+			return nil, err
+		}
+		updated := make([]toggl.ProjectUser, len(ids))
+		for i, id := range ids {
+			old := s.projectUsers[id]
+			pu := &updated[i]
+			if err := patch(pu, old, raw); err != nil {
+				return nil, err
+			}
+			pu.ID, pu.ProjectID, pu.UserID, pu.WorkspaceID = id, old.ProjectID, old.UserID, old.WorkspaceID
+			pu.At = s.stamp()
+		}
+		for i := range updated {
+			s.projectUsers[updated[i].ID] = &updated[i]
+		}
+		if len(ids) > 1 {
+			return data(updated), nil
+		}
+		return data(updated[0]), nil
+	case "DELETE":
+		for _, id := range ids {
+			delete(s.projectUsers, id)
+		}
+		return nil, nil
+	}
+	return nil, errNotFound
+}
+
+func (s *Server) taskNameTaken(t *toggl.Task) bool {
+	for _, o := range s.tasks {
+		if o.ID != t.ID && o.ProjectID == t.ProjectID && strings.EqualFold(o.Name, t.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) serveTasks(r *http.Request, p []string) (interface{}, error) {
+	if len(p) == 1 {
+		if r.Method != "POST" {
+			return nil, errNotFound
+		}
+		raw, err := rawBody(r, "task")
+		if err != nil {
+			return nil, err
+		}
+		t := &toggl.Task{Active: true}
+		if err := patch(t, t, raw); err != nil {
+			return nil, err
+		}
+		project := s.projects[t.ProjectID]
+		if project == nil {
+			return nil, errorf(http.StatusBadRequest, "Project %d not found", t.ProjectID)
+		}
+		t.ID, t.WorkspaceID = 0, project.WorkspaceID
+		if err := checkName(t.Name, s.taskNameTaken(t)); err != nil {
+			return nil, err
+		}
+		t.ID, t.At = s.nextID(), s.stamp()
+		s.tasks[t.ID] = t
+		return data(t), nil
+	}
+
+	ids, err := objects(p, func(id int) bool { return s.tasks[id] != nil })
+	if err != nil || len(p) != 2 {
+		return nil, errNotFound
+	}
+	switch r.Method {
+	case "GET":
+		if len(ids) != 1 {
+			return nil, errNotFound
+		}
+		return data(s.tasks[ids[0]]), nil
+	case "PUT":
+		raw, err := rawBody(r, "task")
+		if err != nil {
+			return nil, err
+		}
+		updated := make([]toggl.Task, len(ids))
+		for i, id := range ids {
+			old := s.tasks[id]
+			t := &updated[i]
+			if err := patch(t, old, raw); err != nil {
+				return nil, err
+			}
+			t.ID, t.ProjectID, t.WorkspaceID = id, old.ProjectID, old.WorkspaceID
+			if err := checkName(t.Name, s.taskNameTaken(t)); err != nil {
+				return nil, err
+			}
+			t.At = s.stamp()
+		}
+		for i := range updated {
+			s.tasks[updated[i].ID] = &updated[i]
+		}
+		if len(ids) > 1 {
+			return data(updated), nil
+		}
+		return data(updated[0]), nil
+	case "DELETE":
+		for _, id := range ids {
+			t := *s.tasks[id]
+			t.At = s.stamp()
+			t.ServerDeletedAt = t.At
+			s.deleted.tasks = append(s.deleted.tasks, t)
+			delete(s.tasks, id)
+		}
+		return nil, nil
+	}
+	return nil, errNotFound
+}
+
+func (s *Server) tagNameTaken(t *toggl.Tag) bool {
+	for _, o := range s.tags {
+		if o.ID != t.ID && o.WorkspaceID == t.WorkspaceID && strings.EqualFold(o.Name, t.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// retag replaces the tag old with new in the time entries of workspace
+// wid, or removes it if new is "".
+func (s *Server) retag(wid int, old, new string) {
+	for _, te := range s.timeEntries {
+		if te.WorkspaceID != wid {
+			continue
+		}
+		var tags []string
+		changed := false
+		for _, tag := range te.Tags {
+			switch {
+			case tag != old:
+				tags = append(tags, tag)
+			case new != "":
+				tags = append(tags, new)
+				changed = true
+			default:
+				changed = true
+			}
+		}
+		if changed {
+			te.Tags, te.At = tags, s.stamp()
+		}
+	}
+}
+
+func (s *Server) serveTags(r *http.Request, p []string) (interface{}, error) {
+	if len(p) == 1 {
+		if r.Method != "POST" {
+			return nil, errNotFound
+		}
+		raw, err := rawBody(r, "tag")
+		if err != nil {
+			return nil, err
+		}
+		t := new(toggl.Tag)
+		if err := patch(t, t, raw); err != nil {
+			return nil, err
+		}
+		t.ID = 0
+		if err := s.checkWorkspace(t.WorkspaceID); err != nil {
+			return nil, err
+		}
+		if err := checkName(t.Name, s.tagNameTaken(t)); err != nil {
+			return nil, err
+		}
+		t.ID = s.nextID()
+		s.tags[t.ID] = t
+		return data(t), nil
+	}
+
+	id, err := object(p, func(id int) bool { return s.tags[id] != nil })
+	if err != nil || len(p) != 2 {
+		return nil, errNotFound
+	}
+	old := s.tags[id]
+	switch r.Method {
+	case "PUT":
+		raw, err := rawBody(r, "tag")
+		if err != nil {
+			return nil, err
+		}
+		t := new(toggl.Tag)
+		if err := patch(t, old, raw); err != nil {
+			return nil, err
+		}
+		t.ID, t.WorkspaceID = id, old.WorkspaceID
+		if err := checkName(t.Name, s.tagNameTaken(t)); err != nil {
+			return nil, err
+		}
+		if t.Name != old.Name {
+			s.retag(t.WorkspaceID, old.Name, t.Name)
+		}
+		s.tags[id] = t
+		return data(t), nil
+	case "DELETE":
+		s.retag(old.WorkspaceID, old.Name, "")
+		t := *old
+		t.ServerDeletedAt = s.stamp()
+		s.deleted.tags = append(s.deleted.tags, t)
+		delete(s.tags, id)
+		return nil, nil
+	}
+	return nil, errNotFound
+}
+
+// checkTimeEntry validates te and sets its workspace from its project or
+// task.
+func (s *Server) checkTimeEntry(te *toggl.TimeEntry) error {
+	if te.CreatedWith == "" {
+		return errorf(http.StatusBadRequest, "created_with needs to be provided a valid string")
+	}
+	if te.TaskID != 0 {
+		t := s.tasks[te.TaskID]
+		if t == nil || (te.ProjectID != 0 && te.ProjectID != t.ProjectID) {
+			return errorf(http.StatusBadRequest, "Task %d not found", te.TaskID)
+		}
+		te.ProjectID = t.ProjectID
+	}
+	if te.ProjectID != 0 {
+		p := s.projects[te.ProjectID]
+		if p == nil {
+			return errorf(http.StatusBadRequest, "Project %d not found", te.ProjectID)
+		}
+		te.WorkspaceID = p.WorkspaceID
+	}
+	if err := s.checkWorkspace(te.WorkspaceID); err != nil {
+		return err
+	}
+	if te.Start == nil {
+		return errorf(http.StatusBadRequest, "Start time is required")
+	}
+	return nil
+}
+
+// stopRunning stops the running time entries of the user.
+func (s *Server) stopRunning() {
+	now := s.now()
+	for _, te := range s.timeEntries {
+		if te.UserID == s.userID && te.IsRunning() {
+			s.stop(te, now)
+		}
+	}
+}
+
+func (s *Server) stop(te *toggl.TimeEntry, now time.Time) {
+	d := te.Elapsed(now).Truncate(time.Second)
+	stop := te.Start.Add(d)
+	te.Stop, te.Duration, te.At = &stop, int(d/time.Second), s.stamp()
+}
+
+// entryResponse returns a copy of te wrapped in a data object.
+func entryResponse(te *toggl.TimeEntry) interface{} {
+	c := new(toggl.TimeEntry)
+	clone(c, te)
+	return data(c)
+}
+
+func (s *Server) serveTimeEntries(r *http.Request, p []string) (interface{}, error) {
+	switch {
+	case len(p) == 1 && r.Method == "GET":
+		return s.listUserTimeEntries(r)
+	case len(p) == 1 && r.Method == "POST":
+		return s.createTimeEntry(r, false)
+	case len(p) == 2 && p[1] == "start" && r.Method == "POST":
+		return s.createTimeEntry(r, true)
+	case len(p) == 2 && p[1] == "current" && r.Method == "GET":
+		for _, te := range s.timeEntries {
+			if te.UserID == s.userID && te.IsRunning() {
+				return entryResponse(te), nil
+			}
+		}
+		return data(nil), nil
+	}
+
+	ids, err := objects(p, func(id int) bool {
+		te := s.timeEntries[id]
+		return te != nil && te.UserID == s.userID
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(p) == 3 && p[2] == "stop" && r.Method == "PUT" && len(ids) == 1:
+		te := s.timeEntries[ids[0]]
+		if !te.IsRunning() {
+			return nil, errorf(http.StatusBadRequest, "Time entry is not running")
+		}
+		s.stop(te, s.now())
+		return entryResponse(te), nil
+	case len(p) != 2:
+		return nil, errNotFound
+	}
+
+	switch r.Method {
+	case "GET":
+		if len(ids) != 1 {
+			return nil, errNotFound
+		}
+		return entryResponse(s.timeEntries[ids[0]]), nil
+	case "PUT":
+		return s.updateTimeEntries(r, ids)
+	case "DELETE":
+		for _, id := range ids {
+			var te toggl.TimeEntry
+			clone(&te, s.timeEntries[id])
+			te.At = s.stamp()
+			te.ServerDeletedAt = te.At
+			s.deleted.timeEntries = append(s.deleted.timeEntries, te)
+			delete(s.timeEntries, id)
+		}
+		return nil, nil
+	}
+	return nil, errNotFound
+}
+
+func (s *Server) listUserTimeEntries(r *http.Request) (interface{}, error) {
+	// Toggl lists the last 9 days by default.
+	end := s.now()
+	start := end.AddDate(0, 0, -9)
+	for key, t := range map[string]*time.Time{"start_date": &start, "end_date": &end} {
+		if v := r.FormValue(key); v != "" {
+			var err error
+			if *t, err = time.Parse(time.RFC3339, v); err != nil {
+				return nil, errorf(http.StatusBadRequest, "Invalid %s %q", key, v)
+			}
+		}
+	}
+
+	entries := s.listTimeEntries(func(te *toggl.TimeEntry) bool {
+		return te.UserID == s.userID && te.Start != nil && !te.Start.Before(start) && !te.Start.After(end)
+	})
+	if len(entries) > toggl.TimeEntriesListLimit {
+		entries = entries[:toggl.TimeEntriesListLimit]
+	}
+	return entries, nil
+}
+
+func (s *Server) createTimeEntry(r *http.Request, start bool) (interface{}, error) {
+	raw, err := rawBody(r, "time_entry")
+	if err != nil {
+		return nil, err
+	}
+	te := new(toggl.TimeEntry)
+	if err := patch(te, te, raw); err != nil {
+		return nil, err
+	}
+	te.ID, te.UserID, te.TagAction = 0, s.userID, ""
+
+	if start {
+		now := s.now().Truncate(time.Second)
+		te.Start, te.Stop, te.Duration = &now, nil, int(-now.Unix())
+	}
+	if err := s.checkTimeEntry(te); err != nil {
+		return nil, err
+	}
+	switch {
+	case te.Duration < 0:
+		te.Stop = nil
+	case te.Duration == 0 && te.Stop != nil:
+		te.Duration = int(te.Stop.Sub(*te.Start) / time.Second)
+	case te.Duration > 0 && te.Stop == nil:
+		stop := te.Start.Add(time.Duration(te.Duration) * time.Second)
+		te.Stop = &stop
+	}
+	if te.Duration == 0 {
+		return nil, errorf(http.StatusBadRequest, "Duration is required")
+	}
+
+	if te.IsRunning() {
+		s.stopRunning()
+	}
+	te.ID, te.At = s.nextID(), s.stamp()
+	s.timeEntries[te.ID] = te
+	return entryResponse(te), nil
+}
+
+func (s *Server) updateTimeEntries(r *http.Request, ids []int) (interface{}, error) {
+	raw, err := rawBody(r, "time_entry")
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	json.Unmarshal(raw, &fields)
+	var changes toggl.TimeEntry
+	if err := json.Unmarshal(raw, &changes); err != nil {
+		return nil, errorf(http.StatusBadRequest, "Invalid JSON: %v", err)
+	}
+
+	updated := make([]*toggl.TimeEntry, len(ids))
+	for i, id := range ids {
+		old := s.timeEntries[id]
+		te := new(toggl.TimeEntry)
+		if err := patch(te, old, raw); err != nil {
+			return nil, err
+		}
+		te.ID, te.UserID = id, old.UserID
+
+		switch changes.TagAction {
+		case toggl.TagActionAdd:
+			te.Tags = append([]string(nil), old.Tags...)
+			for _, tag := range changes.Tags {
+				if !contains(te.Tags, tag) {
+					te.Tags = append(te.Tags, tag)
+				}
+			}
+		case toggl.TagActionRemove:
+			te.Tags = nil
+			for _, tag := range old.Tags {
+				if !contains(changes.Tags, tag) {
+					te.Tags = append(te.Tags, tag)
+				}
+			}
+		}
+		te.TagAction = ""
+
+		// Keep duration, start and stop consistent.
+		if te.Start == nil {
+			return nil, errorf(http.StatusBadRequest, "Start time is required")
+		}
+		_, hasDuration := fields["duration"]
+		switch {
+		case te.IsRunning():
+			te.Stop = nil
+		case hasDuration:
+			stop := te.Start.Add(time.Duration(te.Duration) * time.Second)
+			te.Stop = &stop
+		case te.Start != nil && te.Stop != nil:
+			te.Duration = int(te.Stop.Sub(*te.Start) / time.Second)
+		}
+
+		if err := s.checkTimeEntry(te); err != nil {
+			return nil, err
+		}
+		te.At = s.stamp()
+		updated[i] = te
+	}
+
+	list := make([]toggl.TimeEntry, len(updated))
+	for i, te := range updated {
+		s.timeEntries[te.ID] = te
+		clone(&list[i], te)
+	}
+	// Bulk tag updates are answered with a list, like updates of several
+	// entries.
+	if len(ids) > 1 || changes.TagAction != "" {
+		return data(list), nil
+	}
+	return data(list[0]), nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
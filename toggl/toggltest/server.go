@@ -0,0 +1,349 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package toggltest provides an in-memory fake of the Toggl API v8 for
+testing code built on package toggl.
+
+A Server keeps workspaces, clients, projects, project users, tasks, tags,
+time entries and users in memory and serves them the way Toggl does, so
+that changes made through one request are seen by the next:
+
+	s := toggltest.NewServer()
+	defer s.Close()
+
+	c := s.Client()
+	te, err := c.TimeEntries.Start(&toggl.TimeEntry{
+		Description: "Testing",
+		CreatedWith: "test",
+	})
+
+Like Toggl, the server remembers deleted objects and returns them with
+ServerDeletedAt set by UsersService.MeSince.
+
+Data can be added directly, and failures and latency injected:
+
+	p := s.AddProject(toggl.Project{Name: "Website"})
+	s.Fail(toggltest.Fault{Method: "PUT", Path: "projects/*", StatusCode: 500, Count: 1})
+	s.SetLatency(50 * time.Millisecond)
+*/
+package toggltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+// APIToken is the API token of the user of a new Server.
+const APIToken = "toggltest-api-token"
+
+// Server is a fake Toggl API server. Its methods are safe for concurrent
+// use.
+type Server struct {
+	// URL of the API, ending in a slash, for toggl.WithBaseURL.
+	URL string
+
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	now      func() time.Time
+	lastID   int
+	userID   int
+	latency  time.Duration
+	faults   []*Fault
+	requests []string
+
+	workspaces   map[int]*toggl.Workspace
+	clients      map[int]*toggl.WorkspaceClient
+	projects     map[int]*toggl.Project
+	projectUsers map[int]*toggl.ProjectUser
+	tasks        map[int]*toggl.Task
+	tags         map[int]*toggl.Tag
+	timeEntries  map[int]*toggl.TimeEntry
+	users        map[int]*toggl.User
+
+	// Objects deleted through the API, returned by me?since
+	deleted struct {
+		clients     []toggl.WorkspaceClient
+		projects    []toggl.Project
+		tasks       []toggl.Task
+		tags        []toggl.Tag
+		timeEntries []toggl.TimeEntry
+	}
+}
+
+// NewServer starts and returns a Server with a user, authenticated by
+// APIToken, and the user's default workspace. The caller should call
+// Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		now:          time.Now,
+		workspaces:   make(map[int]*toggl.Workspace),
+		clients:      make(map[int]*toggl.WorkspaceClient),
+		projects:     make(map[int]*toggl.Project),
+		projectUsers: make(map[int]*toggl.ProjectUser),
+		tasks:        make(map[int]*toggl.Task),
+		tags:         make(map[int]*toggl.Tag),
+		timeEntries:  make(map[int]*toggl.TimeEntry),
+		users:        make(map[int]*toggl.User),
+	}
+
+	w := s.AddWorkspace(toggl.Workspace{Name: "Test Workspace", DefaultCurrency: "USD"})
+	u := s.AddUser(toggl.User{
+		Fullname:  "Test User",
+		Email:     "test@example.com",
+		APIToken:  APIToken,
+		DefautWID: w.ID,
+	})
+	s.userID = u.ID
+
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL + "/api/v8/"
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns a client of the server authenticated as its user. opts
+// are applied after the base URL is set.
+func (s *Server) Client(opts ...toggl.ClientOption) *toggl.Client {
+	u, _ := url.Parse(s.URL)
+	return toggl.NewClient(APIToken, append([]toggl.ClientOption{toggl.WithBaseURL(u)}, opts...)...)
+}
+
+// SetClock replaces the clock used for start and stop times and for the
+// At timestamps of changed objects. It defaults to time.Now.
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// SetLatency delays every response by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// Fault makes matching requests fail instead of being served.
+type Fault struct {
+	// Method to match, or "" for any
+	Method string
+
+	// Path to match, relative to URL like "time_entries/1". A trailing
+	// "*" matches any path with that prefix; "" matches any path.
+	Path string
+
+	// Status code and body of the response. A StatusCode of 0 drops the
+	// connection without responding.
+	StatusCode int
+	Body       string
+
+	// Number of requests to fail, or 0 to fail all of them
+	Count int
+}
+
+func (f *Fault) matches(method, p string) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, method) {
+		return false
+	}
+	if strings.HasSuffix(f.Path, "*") {
+		return strings.HasPrefix(p, strings.TrimSuffix(f.Path, "*"))
+	}
+	return f.Path == "" || f.Path == p
+}
+
+// Fail makes requests matching f fail. Faults are matched in the order
+// they were added.
+func (s *Server) Fail(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all faults added by Fail.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the requests received so far, such as
+// "GET time_entries/current", including failed ones.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// apiError is an error response.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func errorf(status int, format string, a ...interface{}) *apiError {
+	return &apiError{status: status, message: fmt.Sprintf(format, a...)}
+}
+
+var errNotFound = errorf(http.StatusNotFound, "Not found")
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	p := strings.TrimPrefix(path.Clean(r.URL.Path), "/api/v8/")
+
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Method+" "+p)
+	latency := s.latency
+	var fault *Fault
+	for i, f := range s.faults {
+		if f.matches(r.Method, p) {
+			fault = f
+			if f.Count--; f.Count == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if latency > 0 {
+		t := time.NewTimer(latency)
+		select {
+		case <-r.Context().Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+	}
+
+	if fault != nil {
+		if fault.StatusCode == 0 {
+			if conn, _, err := w.(http.Hijacker).Hijack(); err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.WriteHeader(fault.StatusCode)
+		fmt.Fprint(w, fault.Body)
+		return
+	}
+
+	if _, pass, _ := r.BasicAuth(); pass != "api_token" || !s.authenticate(r) {
+		http.Error(w, "Incorrect username and/or password", http.StatusForbidden)
+		return
+	}
+
+	v, err := s.route(r, strings.Split(p, "/"))
+	if e, ok := err.(*apiError); ok {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(e.status)
+		json.NewEncoder(w).Encode(e.message)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if v != nil {
+		json.NewEncoder(w).Encode(v)
+	}
+}
+
+// authenticate reports whether the API token of r is the user's.
+func (s *Server) authenticate(r *http.Request) bool {
+	token, _, _ := r.BasicAuth()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return token == s.users[s.userID].APIToken
+}
+
+// data wraps v in the "data" object of Toggl responses.
+func data(v interface{}) interface{} {
+	return struct {
+		Data interface{} `json:"data"`
+	}{v}
+}
+
+// clone copies src to dst, which must be a pointer to a value of the same
+// type, sharing no memory.
+func clone(dst, src interface{}) {
+	b, err := json.Marshal(src)
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(b, dst); err != nil {
+		panic(err)
+	}
+}
+
+// nextID returns a new object ID. s.mu must be held.
+func (s *Server) nextID() int {
+	s.lastID++
+	return s.lastID
+}
+
+// stamp returns the current time for At timestamps. s.mu must be held.
+func (s *Server) stamp() *time.Time {
+	t := s.now().UTC().Truncate(time.Second)
+	return &t
+}
+
+// parseIDs parses comma separated IDs.
+func parseIDs(s string) ([]int, error) {
+	var ids []int
+	for _, f := range strings.Split(s, ",") {
+		var id int
+		if _, err := fmt.Sscan(f, &id); err != nil || id <= 0 {
+			return nil, errNotFound
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// route serves the request for the path segments p and returns the
+// response body. s.mu is locked while the request is served.
+func (s *Server) route(r *http.Request, p []string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch p[0] {
+	case "me":
+		return s.serveMe(r, p)
+	case "workspaces":
+		return s.serveWorkspaces(r, p)
+	case "clients":
+		return s.serveClients(r, p)
+	case "projects":
+		return s.serveProjects(r, p)
+	case "project_users":
+		return s.serveProjectUsers(r, p)
+	case "tasks":
+		return s.serveTasks(r, p)
+	case "tags":
+		return s.serveTags(r, p)
+	case "time_entries":
+		return s.serveTimeEntries(r, p)
+	}
+	return nil, errNotFound
+}
@@ -0,0 +1,228 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggltest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+var now = time.Date(2013, time.July, 1, 12, 0, 0, 0, time.UTC)
+
+func newServer() *Server {
+	s := NewServer()
+	s.SetClock(func() time.Time { return now })
+	return s
+}
+
+func TestServer_timeEntries(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	now := now
+	s.SetClock(func() time.Time { return now })
+	c := s.Client()
+	wid := s.User().DefautWID
+
+	first, err := c.TimeEntries.Start(&toggl.TimeEntry{WorkspaceID: wid, Description: "first", CreatedWith: "test"})
+	if err != nil {
+		t.Fatalf("TimeEntries.Start returned error: %v", err)
+	}
+	if !first.IsRunning() || !first.Start.Equal(now) {
+		t.Errorf("TimeEntries.Start returned %+v, want an entry running since %v", first, now)
+	}
+
+	now = now.Add(time.Hour)
+	second, err := c.TimeEntries.Start(&toggl.TimeEntry{WorkspaceID: wid, Description: "second", CreatedWith: "test"})
+	if err != nil {
+		t.Fatalf("TimeEntries.Start returned error: %v", err)
+	}
+	if got, _ := c.TimeEntries.Get(first.ID); got.Duration != 3600 {
+		t.Errorf("Starting an entry left the running one at duration %v, want 3600", got.Duration)
+	}
+	if current, _ := c.TimeEntries.Current(); current == nil || current.ID != second.ID {
+		t.Errorf("TimeEntries.Current returned %+v, want entry %d", current, second.ID)
+	}
+
+	now = now.Add(30 * time.Minute)
+	if stopped, err := c.TimeEntries.Stop(second.ID); err != nil || stopped.Duration != 1800 {
+		t.Errorf("TimeEntries.Stop returned %+v, %v, want duration 1800", stopped, err)
+	}
+	if current, err := c.TimeEntries.Current(); current != nil || err != nil {
+		t.Errorf("TimeEntries.Current returned %+v, %v, want nil", current, err)
+	}
+
+	if _, err := c.TimeEntries.MassUpdate([]int{first.ID, second.ID}, &toggl.TimeEntry{Tags: []string{"billed"}, TagAction: toggl.TagActionAdd}); err != nil {
+		t.Fatalf("TimeEntries.MassUpdate returned error: %v", err)
+	}
+	if _, err := c.TimeEntries.Update(&toggl.TimeEntry{ID: first.ID, Duration: 600}); err != nil {
+		t.Fatalf("TimeEntries.Update returned error: %v", err)
+	}
+
+	start, end := now.Add(-24*time.Hour), now
+	entries, err := c.TimeEntries.List(&start, &end)
+	if err != nil {
+		t.Fatalf("TimeEntries.List returned error: %v", err)
+	}
+	var got []string
+	for _, te := range entries {
+		got = append(got, te.Description+" "+te.Stop.Sub(*te.Start).String()+" "+te.Tags[0])
+	}
+	if want := []string{"first 10m0s billed", "second 30m0s billed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TimeEntries.List returned %q, want %q", got, want)
+	}
+
+	if err := c.TimeEntries.Delete(first.ID); err != nil {
+		t.Fatalf("TimeEntries.Delete returned error: %v", err)
+	}
+	if _, err := c.TimeEntries.Get(first.ID); !toggl.IsNotFound(err) {
+		t.Errorf("TimeEntries.Get of a deleted entry returned error %v, want not found", err)
+	}
+
+	_, err = c.TimeEntries.Create(&toggl.TimeEntry{WorkspaceID: wid, Start: &now, Duration: 60})
+	if e, ok := err.(*toggl.ErrorResponse); !ok || e.StatusCode != 400 || e.Messages[0] != "created_with needs to be provided a valid string" {
+		t.Errorf("TimeEntries.Create without created_with returned error %v", err)
+	}
+
+	noStart := s.AddTimeEntry(toggl.TimeEntry{WorkspaceID: wid, Duration: 60, CreatedWith: "test"})
+	_, err = c.TimeEntries.Update(&toggl.TimeEntry{ID: noStart.ID, Duration: 600})
+	if e, ok := err.(*toggl.ErrorResponse); !ok || e.StatusCode != 400 || e.Messages[0] != "Start time is required" {
+		t.Errorf("TimeEntries.Update of an entry without start returned error %v", err)
+	}
+}
+
+func TestServer_projects(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+	c := s.Client()
+	wid := s.User().DefautWID
+
+	client, err := c.Clients.Create(&toggl.WorkspaceClient{WorkspaceID: wid, Name: "Acme"})
+	if err != nil {
+		t.Fatalf("Clients.Create returned error: %v", err)
+	}
+	p, err := c.Projects.Create(&toggl.Project{WorkspaceID: wid, ClientID: client.ID, Name: "Website"})
+	if err != nil {
+		t.Fatalf("Projects.Create returned error: %v", err)
+	}
+	if !p.Active || p.At == nil {
+		t.Errorf("Projects.Create returned %+v, want an active project with At", p)
+	}
+
+	_, err = c.Projects.Create(&toggl.Project{WorkspaceID: wid, ClientID: client.ID, Name: "website"})
+	if e, ok := err.(*toggl.ErrorResponse); !ok || e.Messages[0] != "Name has already been taken" {
+		t.Errorf("Projects.Create with a taken name returned error %v", err)
+	}
+
+	if p, err = c.Projects.Update(&toggl.Project{ID: p.ID, Rate: 90}); err != nil || p.Name != "Website" || p.Rate != 90 {
+		t.Errorf("Projects.Update returned %+v, %v, want the renamed project", p, err)
+	}
+	if projects, err := c.Clients.ListClientProjects(client.ID); err != nil || len(projects) != 1 {
+		t.Errorf("Clients.ListClientProjects returned %+v, %v, want 1 project", projects, err)
+	}
+
+	u := s.AddUser(toggl.User{Fullname: "Jane"})
+	pus, err := c.ProjectUsers.MassCreateIDs([]int{s.User().ID, u.ID}, &toggl.ProjectUser{ProjectID: p.ID, Rate: 100})
+	if err != nil || len(pus) != 2 || pus[1].UserID != u.ID || pus[1].WorkspaceID != wid {
+		t.Fatalf("ProjectUsers.MassCreateIDs returned %+v, %v", pus, err)
+	}
+	if pus, _ := c.Projects.ProjectUsers(p.ID); len(pus) != 2 || pus[0].Rate != 100 {
+		t.Errorf("Projects.ProjectUsers returned %+v, want 2 project users", pus)
+	}
+
+	task, err := c.Tasks.Create(&toggl.Task{ProjectID: p.ID, Name: "QA"})
+	if err != nil || task.WorkspaceID != wid || !task.Active {
+		t.Fatalf("Tasks.Create returned %+v, %v", task, err)
+	}
+	other := s.AddTask(toggl.Task{ProjectID: p.ID, WorkspaceID: wid, Name: "Design"})
+	if tasks, err := c.Tasks.MassUpdateIDs([]int{task.ID, other.ID}, &toggl.Task{EstimatedSeconds: 3600}); err != nil || len(tasks) != 2 {
+		t.Errorf("Tasks.MassUpdateIDs returned %+v, %v", tasks, err)
+	}
+	if tasks := s.Tasks(); tasks[0].EstimatedSeconds != 3600 || tasks[0].Name != "QA" {
+		t.Errorf("Server has tasks %+v after MassUpdateIDs", tasks)
+	}
+
+	if ps, _ := c.Workspaces.ListProjects(wid, ""); len(ps) != 1 || ps[0].ID != p.ID {
+		t.Errorf("Workspaces.ListProjects returned %+v", ps)
+	}
+}
+
+func TestServer_tags(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+	c := s.Client()
+	wid := s.User().DefautWID
+
+	tag, err := c.Tags.Create(&toggl.Tag{WorkspaceID: wid, Name: "billed"})
+	if err != nil {
+		t.Fatalf("Tags.Create returned error: %v", err)
+	}
+	te := s.AddTimeEntry(toggl.TimeEntry{WorkspaceID: wid, Start: &now, Duration: 60, Tags: []string{"billed", "x"}})
+
+	if _, err := c.Tags.Update(&toggl.Tag{ID: tag.ID, Name: "invoiced"}); err != nil {
+		t.Fatalf("Tags.Update returned error: %v", err)
+	}
+	if got, _ := c.TimeEntries.Get(te.ID); !reflect.DeepEqual(got.Tags, []string{"invoiced", "x"}) {
+		t.Errorf("Renaming a tag left time entry tags %q", got.Tags)
+	}
+
+	if err := c.Tags.Delete(tag.ID); err != nil {
+		t.Fatalf("Tags.Delete returned error: %v", err)
+	}
+	if got := s.TimeEntries()[0].Tags; !reflect.DeepEqual(got, []string{"x"}) {
+		t.Errorf("Deleting a tag left time entry tags %q", got)
+	}
+
+	me, err := c.Users.Me(true)
+	if err != nil || me.APIToken != APIToken || len(me.TimeEntries) != 1 || len(me.Tags) != 0 {
+		t.Errorf("Users.Me returned %+v, %v", me, err)
+	}
+}
+
+func TestServer_unauthorized(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+
+	c := s.Client(toggl.WithAuthenticator(toggl.APITokenAuth{Token: "wrong"}))
+	if _, err := c.Workspaces.List(); !toggl.IsUnauthorized(err) {
+		t.Errorf("Workspaces.List with a wrong token returned error %v, want unauthorized", err)
+	}
+}
+
+func TestServer_faults(t *testing.T) {
+	s := newServer()
+	defer s.Close()
+
+	s.Fail(Fault{Method: "GET", Path: "workspaces", StatusCode: 503, Count: 1})
+	c := s.Client(toggl.WithRetryPolicy(&toggl.RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}))
+	if ws, err := c.Workspaces.List(); err != nil || len(ws) != 1 {
+		t.Errorf("Workspaces.List returned %+v, %v, want the workspace after a retry", ws, err)
+	}
+	if got, want := s.Requests(), []string{"GET workspaces", "GET workspaces"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Server received %q, want %q", got, want)
+	}
+
+	s.Fail(Fault{Path: "time_entries/*", StatusCode: 429, Body: "Too many requests"})
+	if _, err := c.TimeEntries.Get(1); !toggl.IsRateLimited(err) {
+		t.Errorf("TimeEntries.Get returned error %v, want rate limited", err)
+	}
+	s.ClearFaults()
+
+	s.Fail(Fault{Path: "me"})
+	if _, err := s.Client().Users.Me(false); err == nil {
+		t.Errorf("Users.Me returned no error on a dropped connection")
+	}
+
+	s.SetLatency(time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.Workspaces.ListContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Workspaces.ListContext returned error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
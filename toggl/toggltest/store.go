@@ -0,0 +1,290 @@
+// Copyright 2013 The go-toggl AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package toggltest
+
+import (
+	"sort"
+
+	"github.com/gedex/go-toggl/toggl"
+)
+
+// The Add methods store an object as is, without the validation applied
+// to API requests. An object with an ID of 0 gets a new one, and its At
+// timestamp is set if missing. The stored object is returned.
+
+// assignID returns id, or a new ID if it is 0. s.mu must be held.
+func (s *Server) assignID(id int) int {
+	if id == 0 {
+		return s.nextID()
+	}
+	if id > s.lastID {
+		s.lastID = id
+	}
+	return id
+}
+
+// AddWorkspace adds a workspace.
+func (s *Server) AddWorkspace(w toggl.Workspace) toggl.Workspace {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.ID = s.assignID(w.ID)
+	if w.At == nil {
+		w.At = s.stamp()
+	}
+	s.workspaces[w.ID] = &w
+	return w
+}
+
+// AddClient adds a client.
+func (s *Server) AddClient(c toggl.WorkspaceClient) toggl.WorkspaceClient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.ID = s.assignID(c.ID)
+	if c.At == nil {
+		c.At = s.stamp()
+	}
+	s.clients[c.ID] = &c
+	return c
+}
+
+// AddProject adds a project.
+func (s *Server) AddProject(p toggl.Project) toggl.Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p.ID = s.assignID(p.ID)
+	if p.At == nil {
+		p.At = s.stamp()
+	}
+	s.projects[p.ID] = &p
+	return p
+}
+
+// AddProjectUser adds a project user.
+func (s *Server) AddProjectUser(pu toggl.ProjectUser) toggl.ProjectUser {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pu.ID = s.assignID(pu.ID)
+	if pu.At == nil {
+		pu.At = s.stamp()
+	}
+	s.projectUsers[pu.ID] = &pu
+	return pu
+}
+
+// AddTask adds a task.
+func (s *Server) AddTask(t toggl.Task) toggl.Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.ID = s.assignID(t.ID)
+	if t.At == nil {
+		t.At = s.stamp()
+	}
+	s.tasks[t.ID] = &t
+	return t
+}
+
+// AddTag adds a tag.
+func (s *Server) AddTag(t toggl.Tag) toggl.Tag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.ID = s.assignID(t.ID)
+	s.tags[t.ID] = &t
+	return t
+}
+
+// AddTimeEntry adds a time entry. Its UserID defaults to the server's
+// user.
+func (s *Server) AddTimeEntry(te toggl.TimeEntry) toggl.TimeEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	te.ID = s.assignID(te.ID)
+	if te.UserID == 0 {
+		te.UserID = s.userID
+	}
+	if te.At == nil {
+		te.At = s.stamp()
+	}
+	stored := new(toggl.TimeEntry)
+	clone(stored, &te)
+	s.timeEntries[te.ID] = stored
+	return te
+}
+
+// AddUser adds a user.
+func (s *Server) AddUser(u toggl.User) toggl.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u.ID = s.assignID(u.ID)
+	if u.At == nil {
+		u.At = s.stamp()
+	}
+	s.users[u.ID] = &u
+	return u
+}
+
+// User returns the user the server authenticates requests as.
+func (s *Server) User() toggl.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.users[s.userID]
+}
+
+// Workspaces returns the workspaces, ordered by ID.
+func (s *Server) Workspaces() []toggl.Workspace {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listWorkspaces(func(*toggl.Workspace) bool { return true })
+}
+
+// Clients returns the clients, ordered by ID.
+func (s *Server) Clients() []toggl.WorkspaceClient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listClients(func(*toggl.WorkspaceClient) bool { return true })
+}
+
+// Projects returns the projects, ordered by ID.
+func (s *Server) Projects() []toggl.Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listProjects(func(*toggl.Project) bool { return true })
+}
+
+// ProjectUsers returns the project users, ordered by ID.
+func (s *Server) ProjectUsers() []toggl.ProjectUser {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listProjectUsers(func(*toggl.ProjectUser) bool { return true })
+}
+
+// Tasks returns the tasks, ordered by ID.
+func (s *Server) Tasks() []toggl.Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listTasks(func(*toggl.Task) bool { return true })
+}
+
+// Tags returns the tags, ordered by ID.
+func (s *Server) Tags() []toggl.Tag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listTags(func(*toggl.Tag) bool { return true })
+}
+
+// TimeEntries returns the time entries, ordered by ID.
+func (s *Server) TimeEntries() []toggl.TimeEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := s.listTimeEntries(func(*toggl.TimeEntry) bool { return true })
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries
+}
+
+// Users returns the users, ordered by ID.
+func (s *Server) Users() []toggl.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listUsers(func(*toggl.User) bool { return true })
+}
+
+// The list methods return copies of the objects matching a filter,
+// ordered by ID, or time entries by start time. s.mu must be held.
+
+func (s *Server) listWorkspaces(match func(*toggl.Workspace) bool) []toggl.Workspace {
+	list := []toggl.Workspace{}
+	for _, w := range s.workspaces {
+		if match(w) {
+			list = append(list, *w)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func (s *Server) listClients(match func(*toggl.WorkspaceClient) bool) []toggl.WorkspaceClient {
+	list := []toggl.WorkspaceClient{}
+	for _, c := range s.clients {
+		if match(c) {
+			list = append(list, *c)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func (s *Server) listProjects(match func(*toggl.Project) bool) []toggl.Project {
+	list := []toggl.Project{}
+	for _, p := range s.projects {
+		if match(p) {
+			list = append(list, *p)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func (s *Server) listProjectUsers(match func(*toggl.ProjectUser) bool) []toggl.ProjectUser {
+	list := []toggl.ProjectUser{}
+	for _, pu := range s.projectUsers {
+		if match(pu) {
+			list = append(list, *pu)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func (s *Server) listTasks(match func(*toggl.Task) bool) []toggl.Task {
+	list := []toggl.Task{}
+	for _, t := range s.tasks {
+		if match(t) {
+			list = append(list, *t)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func (s *Server) listTags(match func(*toggl.Tag) bool) []toggl.Tag {
+	list := []toggl.Tag{}
+	for _, t := range s.tags {
+		if match(t) {
+			list = append(list, *t)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func (s *Server) listTimeEntries(match func(*toggl.TimeEntry) bool) []toggl.TimeEntry {
+	list := []toggl.TimeEntry{}
+	for _, te := range s.timeEntries {
+		if match(te) {
+			var c toggl.TimeEntry
+			clone(&c, te)
+			list = append(list, c)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Start == nil || b.Start == nil || a.Start.Equal(*b.Start) {
+			return a.ID < b.ID
+		}
+		return a.Start.Before(*b.Start)
+	})
+	return list
+}
+
+func (s *Server) listUsers(match func(*toggl.User) bool) []toggl.User {
+	list := []toggl.User{}
+	for _, u := range s.users {
+		if match(u) {
+			list = append(list, *u)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
@@ -6,6 +6,8 @@
 package toggl
 
 import (
+	"context"
+	"fmt"
 	"time"
 )
 
@@ -65,11 +67,13 @@ type User struct {
 	// Timestamp of last changes, e.g. "2013-03-06T12:18:42+00:00"
 	At *time.Time `json:"at,omitempty"`
 
-	TimeEntries []TimeEntry `json:"time_entries,omitempty"`
-	Projects    []Project   `json:"projects,omitempty"`
-	Tags        []Tag       `json:"tags,omitempty"`
-	// workspaces []
-	// clients []
+	// Related data, see UsersService.Me
+	TimeEntries []TimeEntry       `json:"time_entries,omitempty"`
+	Projects    []Project         `json:"projects,omitempty"`
+	Tags        []Tag             `json:"tags,omitempty"`
+	Workspaces  []Workspace       `json:"workspaces,omitempty"`
+	Clients     []WorkspaceClient `json:"clients,omitempty"`
+	Tasks       []Task            `json:"tasks,omitempty"`
 }
 
 // UserSignup represents posted data to be sent to Signup endpoint.
@@ -88,11 +92,16 @@ type UserCredential struct {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/users.md#get-current-user-data
 func (s *UsersService) Me(withRelatedData bool) (*User, error) {
+	return s.MeContext(context.Background(), withRelatedData)
+}
+
+// MeContext is like Me, but with the provided context.
+func (s *UsersService) MeContext(ctx context.Context, withRelatedData bool) (*User, error) {
 	u := "me"
 	if withRelatedData {
 		u += "?with_related_data=true"
 	}
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -103,13 +112,48 @@ func (s *UsersService) Me(withRelatedData bool) (*User, error) {
 	return data.Data, err
 }
 
+// MeSince returns current user data with the related objects changed
+// since the given Unix time, including deleted ones, which have
+// ServerDeletedAt set. A since of 0 returns all related objects. The
+// response's Since is the server time to pass on the next call.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/users.md#get-current-user-data
+func (s *UsersService) MeSince(since int) (*UserResponse, error) {
+	return s.MeSinceContext(context.Background(), since)
+}
+
+// MeSinceContext is like MeSince, but with the provided context.
+func (s *UsersService) MeSinceContext(ctx context.Context, since int) (*UserResponse, error) {
+	u := "me?with_related_data=true"
+	if since > 0 {
+		u += fmt.Sprintf("&since=%d", since)
+	}
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data := new(UserResponse)
+	_, err = s.client.Do(req, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
 // Signup new user.
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/users.md#sign-up-new-user
 func (s *UsersService) Signup(uc *UserCredential) (*User, error) {
+	return s.SignupContext(context.Background(), uc)
+}
+
+// SignupContext is like Signup, but with the provided context.
+func (s *UsersService) SignupContext(ctx context.Context, uc *UserCredential) (*User, error) {
 	u := "signups"
 	us := &UserSignup{uc}
-	req, err := s.client.NewRequest("POST", u, us)
+	req, err := s.client.NewRequestContext(ctx, "POST", u, us)
 	if err != nil {
 		return nil, err
 	}
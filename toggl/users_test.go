@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestUsersService_Me(t *testing.T) {
@@ -61,3 +62,33 @@ func TestUsersService_Signup(t *testing.T) {
 		t.Errorf("Users.Signup returned %+v, want %+v", newUser, want)
 	}
 }
+
+func TestUsersService_MeSince(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testFormValues(t, r, values{"with_related_data": "true", "since": "1370000000"})
+		fmt.Fprint(w, `{"since": 1370000100, "data": {"id": 1, "clients": [{"id": 2}],
+			"tasks": [{"id": 3, "server_deleted_at": "2013-06-01T00:00:00Z"}]}}`)
+	})
+
+	result, err := client.Users.MeSince(1370000000)
+	if err != nil {
+		t.Errorf("Users.MeSince returned error: %v", err)
+	}
+
+	deleted := time.Date(2013, time.June, 1, 0, 0, 0, 0, time.UTC)
+	want := &UserResponse{
+		Since: 1370000100,
+		Data: &User{
+			ID:      1,
+			Clients: []WorkspaceClient{{ID: 2}},
+			Tasks:   []Task{{ID: 3, ServerDeletedAt: &deleted}},
+		},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Users.MeSince returned %+v, want %+v", result, want)
+	}
+}
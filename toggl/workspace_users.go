@@ -6,6 +6,7 @@
 package toggl
 
 import (
+	"context"
 	"errors"
 	"fmt"
 )
@@ -42,6 +43,11 @@ type WorkspaceUserCreate struct {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/workspace_users.md#update-workspace-user
 func (s *WorkspaceUsersService) Update(wu *WorkspaceUser) (*WorkspaceUser, error) {
+	return s.UpdateContext(context.Background(), wu)
+}
+
+// UpdateContext is like Update, but with the provided context.
+func (s *WorkspaceUsersService) UpdateContext(ctx context.Context, wu *WorkspaceUser) (*WorkspaceUser, error) {
 	if wu == nil {
 		return nil, errors.New("WorkspacesUsers cannot be nil")
 	}
@@ -52,7 +58,7 @@ func (s *WorkspaceUsersService) Update(wu *WorkspaceUser) (*WorkspaceUser, error
 	u := fmt.Sprintf("workspace_users/%v", wu.ID)
 
 	wuc := &WorkspaceUserCreate{wu}
-	req, err := s.client.NewRequest("PUT", u, wuc)
+	req, err := s.client.NewRequestContext(ctx, "PUT", u, wuc)
 	if err != nil {
 		return nil, err
 	}
@@ -67,8 +73,13 @@ func (s *WorkspaceUsersService) Update(wu *WorkspaceUser) (*WorkspaceUser, error
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/workspace_users.md#delete-workspace-user
 func (s *WorkspaceUsersService) Delete(id int) error {
+	return s.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is like Delete, but with the provided context.
+func (s *WorkspaceUsersService) DeleteContext(ctx context.Context, id int) error {
 	u := fmt.Sprintf("workspace_users/%v", id)
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, nil)
 	if err != nil {
 		return err
 	}
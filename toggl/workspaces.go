@@ -6,6 +6,7 @@
 package toggl
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -20,19 +21,28 @@ type WorkspacesService struct {
 
 // Workspace represents workspace of Toggl's user.
 type Workspace struct {
-	ID      int        `json:"id,omitempty"`
-	Name    string     `json:"name,omitempty"`
-	Premium bool       `json:"premium,omitempty"`
-	At      *time.Time `json:"at,omitempty"`
+	ID                int        `json:"id,omitempty"`
+	Name              string     `json:"name,omitempty"`
+	Premium           bool       `json:"premium,omitempty"`
+	DefaultHourlyRate float64    `json:"default_hourly_rate,omitempty"`
+	DefaultCurrency   string     `json:"default_currency,omitempty"`
+	Rounding          int        `json:"rounding,omitempty"`         // -1 down, 0 nearest, 1 up
+	RoundingMinutes   int        `json:"rounding_minutes,omitempty"` // round durations to this many minutes
+	At                *time.Time `json:"at,omitempty"`
 }
 
 // List user's workspace.
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/workspaces.md#get-workspaces
 func (s *WorkspacesService) List() ([]Workspace, error) {
+	return s.ListContext(context.Background())
+}
+
+// ListContext is like List, but with the provided context.
+func (s *WorkspacesService) ListContext(ctx context.Context) ([]Workspace, error) {
 	u := "workspaces"
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -47,8 +57,13 @@ func (s *WorkspacesService) List() ([]Workspace, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/workspaces.md#get-workspace-users
 func (s *WorkspacesService) ListUsers(id int) ([]User, error) {
+	return s.ListUsersContext(context.Background(), id)
+}
+
+// ListUsersContext is like ListUsers, but with the provided context.
+func (s *WorkspacesService) ListUsersContext(ctx context.Context, id int) ([]User, error) {
 	u := fmt.Sprintf("workspaces/%v/users", id)
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -63,8 +78,13 @@ func (s *WorkspacesService) ListUsers(id int) ([]User, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/workspaces.md#get-workspace-clients
 func (s *WorkspacesService) ListClients(id int) ([]WorkspaceClient, error) {
+	return s.ListClientsContext(context.Background(), id)
+}
+
+// ListClientsContext is like ListClients, but with the provided context.
+func (s *WorkspacesService) ListClientsContext(ctx context.Context, id int) ([]WorkspaceClient, error) {
 	u := fmt.Sprintf("workspaces/%v/clients", id)
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -79,12 +99,17 @@ func (s *WorkspacesService) ListClients(id int) ([]WorkspaceClient, error) {
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/workspaces.md#get-workspace-projects
 func (s *WorkspacesService) ListProjects(id int, filter string) ([]Project, error) {
+	return s.ListProjectsContext(context.Background(), id, filter)
+}
+
+// ListProjectsContext is like ListProjects, but with the provided context.
+func (s *WorkspacesService) ListProjectsContext(ctx context.Context, id int, filter string) ([]Project, error) {
 	u := fmt.Sprintf("workspaces/%v/projects", id)
 	if filter != "" {
 		u += "?filter=" + filter
 	}
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -99,12 +124,17 @@ func (s *WorkspacesService) ListProjects(id int, filter string) ([]Project, erro
 //
 // Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/workspaces.md#get-workspace-tasks
 func (s *WorkspacesService) ListTasks(id int, filter string) ([]Task, error) {
+	return s.ListTasksContext(context.Background(), id, filter)
+}
+
+// ListTasksContext is like ListTasks, but with the provided context.
+func (s *WorkspacesService) ListTasksContext(ctx context.Context, id int, filter string) ([]Task, error) {
 	u := fmt.Sprintf("workspaces/%v/tasks", id)
 	if filter != "" {
 		u += "?filter=" + filter
 	}
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -114,3 +144,24 @@ func (s *WorkspacesService) ListTasks(id int, filter string) ([]Task, error) {
 
 	return *data, err
 }
+
+// ListTags returns list of tags on specified workspace id.
+//
+// Toggl API docs: https://github.com/toggl/toggl_api_docs/blob/master/chapters/workspaces.md#get-workspace-tags
+func (s *WorkspacesService) ListTags(id int) ([]Tag, error) {
+	return s.ListTagsContext(context.Background(), id)
+}
+
+// ListTagsContext is like ListTags, but with the provided context.
+func (s *WorkspacesService) ListTagsContext(ctx context.Context, id int) ([]Tag, error) {
+	u := fmt.Sprintf("workspaces/%v/tags", id)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data := new([]Tag)
+	_, err = s.client.Do(req, data)
+
+	return *data, err
+}
@@ -111,3 +111,23 @@ func TestWorkspacesService_ListTasks(t *testing.T) {
 		t.Errorf("Workspaces.ListTasks returned %v, want %v", result, want)
 	}
 }
+
+func TestWorkspacesService_ListTags(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/workspaces/1/tags", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w, `[{"id": 1, "name": "billed"}]`)
+	})
+
+	result, err := client.Workspaces.ListTags(1)
+	if err != nil {
+		t.Errorf("Workspaces.ListTags returned error: %v", err)
+	}
+
+	want := []Tag{{ID: 1, Name: "billed"}}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Workspaces.ListTags returned %v, want %v", result, want)
+	}
+}